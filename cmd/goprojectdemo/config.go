@@ -16,19 +16,125 @@ type Config struct {
 	// ServiceResponseTimeout - timeout for service execution
 	ServiceResponseTimeout time.Duration `default:"30s"`
 
+	// ServiceWorkers - maximum number of project stats requests processed at once, shared by all service requests
+	ServiceWorkers int `default:"20"`
+
+	// ServiceWorkerMinTimeLeft - queued project stats requests are dropped when less time is left before deadline
+	ServiceWorkerMinTimeLeft time.Duration `default:"1s"`
+
+	// ServiceDegradedResponseMargin - if greater than 0, contributors rankings are computed from projects stats
+	// retrieved until this time before deadline and returned as partial, instead of failing with timeout.
+	// Disabled by default
+	ServiceDegradedResponseMargin time.Duration `default:"0"`
+
+	// ContributorsExcludeBots - exclude automation accounts (like dependabot[bot]) from rankings.
+	// Disabled by default, set CONTRIBUTORSEXCLUDEBOTS=true to enable
+	ContributorsExcludeBots bool `default:"false"`
+
+	// ContributorsAllowList - comma separated login patterns never excluded from rankings by bots detection or deny list.
+	// Logins excluded explicitly by a request are excluded anyway. Patterns are globs, or regular expressions prefixed with "re:"
+	ContributorsAllowList []string `default:""`
+
+	// ContributorsDenyList - comma separated login patterns always excluded from rankings
+	ContributorsDenyList []string `default:""`
+
+	// ContributorsAliasesFile - path to json file mapping contributor's login to logins of its other accounts.
+	// Stats of these accounts are merged. If empty, only aliases managed by admin api are used
+	ContributorsAliasesFile string `default:""`
+
+	// AdminAPIToken - bearer token required by admin http api. If empty, admin api is disabled
+	AdminAPIToken string `default:""`
+
+	// SnapshotLanguages - comma separated languages for which leaderboard snapshots are taken periodically.
+	// If empty, snapshots are not taken
+	SnapshotLanguages []string `default:""`
+
+	// SnapshotInterval - time between leaderboard snapshots
+	SnapshotInterval time.Duration `default:"24h"`
+
+	// SnapshotRetryInterval - time after which failed snapshot is retried
+	SnapshotRetryInterval time.Duration `default:"5m"`
+
+	// SnapshotProjectsCount - number of top projects leaderboard snapshots are computed from
+	SnapshotProjectsCount int `default:"10"`
+
+	// SnapshotContributorsCount - number of contributors stored in leaderboard snapshots
+	SnapshotContributorsCount int `default:"50"`
+
+	// GithubAPIKind - github api used to retrieve projects and stats: "rest" or "graphql".
+	// GraphQL api retrieves stats of many projects in a single request, but requires auth token
+	GithubAPIKind string `default:"rest"`
+
 	// GithubAPIAddress - address for rest api with protocol
 	GithubAPIAddress string `default:"https://api.github.com"`
 
+	// GithubGraphQLAPIAddress - address for graphql api with protocol
+	GithubGraphQLAPIAddress string `default:"https://api.github.com/graphql"`
+
+	// GithubGraphQLMaxCommits - maximum number of commits retrieved for project's stats by graphql api.
+	// Only the most recent commits of projects with longer history are counted
+	GithubGraphQLMaxCommits int `default:"1000"`
+
+	// GithubEnterpriseURL - base url of GitHub Enterprise Server, e.g. "https://github.example.com".
+	// If set, overrides GithubAPIAddress and GithubGraphQLAPIAddress with the server's "/api/v3" and "/api/graphql"
+	GithubEnterpriseURL string `default:""`
+
+	// GithubCAFile - path to PEM bundle of root CAs trusted by github api client, in addition to the system ones
+	GithubCAFile string `default:""`
+
+	// GithubClientCertFile - path to PEM client certificate for github api client. Requires GithubClientKeyFile
+	GithubClientCertFile string `default:""`
+
+	// GithubClientKeyFile - path to PEM key of the client certificate
+	GithubClientKeyFile string `default:""`
+
+	// GithubProxyURL - proxy for github api requests. If empty, HTTP_PROXY and HTTPS_PROXY env variables are used
+	GithubProxyURL string `default:""`
+
+	// GithubHTTPTimeout - timeout for a single github api request
+	GithubHTTPTimeout time.Duration `default:"30s"`
+
+	// GithubHTTPDialTimeout - timeout for establishing connection to github api
+	GithubHTTPDialTimeout time.Duration `default:"10s"`
+
+	// GithubHTTPTLSHandshakeTimeout - timeout for tls handshake with github api
+	GithubHTTPTLSHandshakeTimeout time.Duration `default:"10s"`
+
+	// GithubHTTPIdleConnTimeout - time after which idle connection to github api is closed
+	GithubHTTPIdleConnTimeout time.Duration `default:"90s"`
+
+	// GithubHTTPMaxIdleConns - maximum number of idle connections to github api
+	GithubHTTPMaxIdleConns int `default:"100"`
+
+	// GithubHTTPMaxIdleConnsPerHost - maximum number of idle connections to a single github api host
+	GithubHTTPMaxIdleConnsPerHost int `default:"20"`
+
+	// GithubHTTPMaxConnsPerHost - maximum number of connections to a single github api host, 0 means no limit
+	GithubHTTPMaxConnsPerHost int `default:"0"`
+
 	// GithubAPIToken - auth token for rest github api (optional, rate limit is lower without this token)
 	GithubAPIToken string `default:""`
 
-	// GithubAPIRateLimit - max frequency for github rest api calls
+	// GithubAPITokens - comma separated list of auth tokens used in rotation (optional, overrides GithubAPIToken).
+	// Each request uses token with the most remaining quota
+	GithubAPITokens []string `default:""`
+
+	// GithubAppID - github app id. If set, requests are authenticated as the app's installation instead of with tokens
+	GithubAppID int64 `default:"0"`
+
+	// GithubAppInstallationID - id of the app's installation, required with GithubAppID
+	GithubAppInstallationID int64 `default:"0"`
+
+	// GithubAppPrivateKeyFile - path to the app's private key file in PEM format, required with GithubAppID
+	GithubAppPrivateKeyFile string `default:""`
+
+	// GithubAPIRateLimit - max frequency for github api calls, per token. Calls are also paced by quota reported by github
 	GithubAPIRateLimit float64 `default:"0.5"`
 
-	// GithubClientCacheSize - maximum number of elements in cache for each github client method
+	// GithubClientCacheSize - maximum number of elements in cache for each forge client method
 	GithubClientCacheSize int `default:"10000"`
 
-	// GithubClientCacheTTL - maximum lifetime for github client cache entries
+	// GithubClientCacheTTL - maximum lifetime for forge client cache entries
 	GithubClientCacheTTL time.Duration `default:"10m"`
 
 	// GithubDBPath - filepath for bolt db data
@@ -37,9 +143,63 @@ type Config struct {
 	// GithubDBBucketName - bolt db bucket name
 	GithubDBBucketName string `default:"github"`
 
-	// GithubDBDataTTL - maximum lifetime for staled data in db
+	// GithubDBDataTTL - maximum lifetime for staled data in db, for all forges
 	GithubDBDataTTL time.Duration `default:"8h"`
 
-	// GithubDBDataRefreshTTL - maximum lifetime for staled data to be queued for refresh
+	// GithubDBDataRefreshTTL - maximum lifetime for staled data to be queued for refresh, for all forges
 	GithubDBDataRefreshTTL time.Duration `default:"1h"`
+
+	// GitlabAPIAddress - address for gitlab rest api with protocol, e.g. "https://gitlab.com/api/v4".
+	// If empty, gitlab forge is disabled
+	GitlabAPIAddress string `default:""`
+
+	// GitlabAPIToken - access token for gitlab api (optional, only public projects are available without this token)
+	GitlabAPIToken string `default:""`
+
+	// GitlabAPIRateLimit - max frequency for gitlab api calls
+	GitlabAPIRateLimit float64 `default:"2"`
+
+	// GitlabCAFile - path to PEM bundle of root CAs trusted by gitlab api client, in addition to the system ones
+	GitlabCAFile string `default:""`
+
+	// GitlabClientCertFile - path to PEM client certificate for gitlab api client. Requires GitlabClientKeyFile
+	GitlabClientCertFile string `default:""`
+
+	// GitlabClientKeyFile - path to PEM key of the client certificate
+	GitlabClientKeyFile string `default:""`
+
+	// GitlabProxyURL - proxy for gitlab api requests. If empty, HTTP_PROXY and HTTPS_PROXY env variables are used
+	GitlabProxyURL string `default:""`
+
+	// GitlabHTTPTimeout - timeout for a single gitlab api request
+	GitlabHTTPTimeout time.Duration `default:"30s"`
+
+	// GiteaAPIAddress - address for gitea or forgejo rest api with protocol, e.g. "https://codeberg.org/api/v1".
+	// If empty, gitea forge is disabled
+	GiteaAPIAddress string `default:""`
+
+	// GiteaAPIToken - access token for gitea api (optional, only public projects are available without this token)
+	GiteaAPIToken string `default:""`
+
+	// GiteaAPIRateLimit - max frequency for gitea api calls
+	GiteaAPIRateLimit float64 `default:"2"`
+
+	// GiteaCommitsMaxPages - maximum number of pages of 50 commits retrieved for project's stats.
+	// Only the most recent commits of projects with longer history are counted
+	GiteaCommitsMaxPages int `default:"20"`
+
+	// GiteaCAFile - path to PEM bundle of root CAs trusted by gitea api client, in addition to the system ones
+	GiteaCAFile string `default:""`
+
+	// GiteaClientCertFile - path to PEM client certificate for gitea api client. Requires GiteaClientKeyFile
+	GiteaClientCertFile string `default:""`
+
+	// GiteaClientKeyFile - path to PEM key of the client certificate
+	GiteaClientKeyFile string `default:""`
+
+	// GiteaProxyURL - proxy for gitea api requests. If empty, HTTP_PROXY and HTTPS_PROXY env variables are used
+	GiteaProxyURL string `default:""`
+
+	// GiteaHTTPTimeout - timeout for a single gitea api request
+	GiteaHTTPTimeout time.Duration `default:"30s"`
 }
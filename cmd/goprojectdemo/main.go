@@ -1,12 +1,20 @@
 package main
 
 import (
-	netHttp "net/http"
+	"context"
+	"expvar"
+	"fmt"
+	"io/ioutil"
 	"sync"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	"github.com/m-zajac/goprojectdemo/internal/adapter/gitea"
 	"github.com/m-zajac/goprojectdemo/internal/adapter/github"
+	"github.com/m-zajac/goprojectdemo/internal/adapter/gitlab"
+	"github.com/m-zajac/goprojectdemo/internal/adapter/identity"
+	"github.com/m-zajac/goprojectdemo/internal/adapter/snapshot"
 	"github.com/m-zajac/goprojectdemo/internal/api/grpc"
 	"github.com/m-zajac/goprojectdemo/internal/api/http"
 	"github.com/m-zajac/goprojectdemo/internal/api/http/limiter"
@@ -24,13 +32,71 @@ func main() {
 		l.Fatalf("coludn't parse config: %v", err)
 	}
 
-	httpClient := &netHttp.Client{
-		Timeout: 30 * time.Second,
+	if conf.GithubEnterpriseURL != "" {
+		restAddress, graphQLAddress, err := github.EnterpriseAddresses(conf.GithubEnterpriseURL)
+		if err != nil {
+			l.Fatalf("invalid github enterprise config: %v", err)
+		}
+		conf.GithubAPIAddress = restAddress
+		conf.GithubGraphQLAPIAddress = graphQLAddress
+	}
+	httpClient, err := forge.NewHTTPClient(forge.HTTPClientConfig{
+		Timeout:             conf.GithubHTTPTimeout,
+		DialTimeout:         conf.GithubHTTPDialTimeout,
+		TLSHandshakeTimeout: conf.GithubHTTPTLSHandshakeTimeout,
+		IdleConnTimeout:     conf.GithubHTTPIdleConnTimeout,
+		MaxIdleConns:        conf.GithubHTTPMaxIdleConns,
+		MaxIdleConnsPerHost: conf.GithubHTTPMaxIdleConnsPerHost,
+		MaxConnsPerHost:     conf.GithubHTTPMaxConnsPerHost,
+		CAFile:              conf.GithubCAFile,
+		ClientCertFile:      conf.GithubClientCertFile,
+		ClientKeyFile:       conf.GithubClientKeyFile,
+		ProxyURL:            conf.GithubProxyURL,
+	})
+	if err != nil {
+		l.Fatalf("invalid github http client config: %v", err)
 	}
-	limitedHTTPClient := limiter.NewHTTPDoer(
+	limitedHTTPClient := limiter.NewAdaptiveHTTPDoer(
 		httpClient,
 		conf.GithubAPIRateLimit,
 	)
+	var githubCredentials github.CredentialProvider = github.StaticToken(conf.GithubAPIToken)
+	if conf.GithubAppID != 0 {
+		if len(conf.GithubAPITokens) > 0 {
+			l.Fatal("github app authentication can't be used with token pool")
+		}
+		privateKey, err := ioutil.ReadFile(conf.GithubAppPrivateKeyFile)
+		if err != nil {
+			l.Fatalf("couldn't read github app private key: %v", err)
+		}
+		githubCredentials, err = github.NewAppCredentials(
+			httpClient,
+			conf.GithubAPIAddress,
+			conf.GithubAppID,
+			conf.GithubAppInstallationID,
+			privateKey,
+		)
+		if err != nil {
+			l.Fatalf("couldn't create github app credentials: %v", err)
+		}
+	}
+	if len(conf.GithubAPITokens) > 0 {
+		tokenPool, err := limiter.NewTokenPool(
+			httpClient,
+			conf.GithubAPITokens,
+			conf.GithubAPIRateLimit,
+		)
+		if err != nil {
+			l.Fatalf("couldn't create github token pool: %v", err)
+		}
+		// Expvars are served only by the profiler server.
+		expvar.Publish("githubTokens", expvar.Func(func() interface{} {
+			return tokenPool.Health()
+		}))
+		limitedHTTPClient = tokenPool
+		// Requests are authorized by the pool.
+		githubCredentials = github.StaticToken("")
+	}
 
 	kvStore, err := database.NewBoltKVStore(
 		conf.GithubDBPath,
@@ -41,38 +107,152 @@ func main() {
 	}
 	defer kvStore.Close()
 
-	githubClient := github.NewClient(
-		limitedHTTPClient,
-		conf.GithubAPIAddress,
-		conf.GithubAPIToken,
-	)
-	githubStaleDataClient, err := github.NewClientWithStaleData(
-		githubClient,
-		kvStore,
-		conf.GithubDBDataTTL,
-		conf.GithubDBDataRefreshTTL,
-		l.WithField("component", "githubStaleDataClient"),
-	)
+	var githubClient app.ForgeClient
+	switch conf.GithubAPIKind {
+	case "rest":
+		githubClient = github.NewClientWithCredentials(
+			limitedHTTPClient,
+			conf.GithubAPIAddress,
+			githubCredentials,
+		)
+	case "graphql":
+		githubClient, err = github.NewGraphQLClientWithCredentials(
+			limitedHTTPClient,
+			conf.GithubGraphQLAPIAddress,
+			githubCredentials,
+			conf.GithubGraphQLMaxCommits,
+			l.WithField("component", "githubGraphQLClient"),
+		)
+		if err != nil {
+			l.Fatalf("couldn't create github graphql client: %v", err)
+		}
+	default:
+		l.Fatalf("invalid github api kind: '%s'", conf.GithubAPIKind)
+	}
+	githubCachedClient, closeGithubClient, err := newCachedForgeClient(githubClient, kvStore, conf, l, app.ForgeGithub)
 	if err != nil {
-		l.Fatalf("coludn't create github db client: %v", err)
+		l.Fatalf("couldn't create github client: %v", err)
 	}
-	githubStaleDataClient.RunScheduler()
-	defer githubStaleDataClient.Close()
-	githubCachedClient, err := github.NewCachedClient(
-		githubStaleDataClient,
-		conf.GithubClientCacheSize,
-		conf.GithubClientCacheTTL,
+	defer closeGithubClient()
+	forges := app.Forges{
+		app.ForgeGithub: githubCachedClient,
+	}
+
+	if conf.GitlabAPIAddress != "" {
+		gitlabHTTPClient, err := newForgeHTTPClient(
+			conf.GitlabHTTPTimeout,
+			conf.GitlabCAFile,
+			conf.GitlabClientCertFile,
+			conf.GitlabClientKeyFile,
+			conf.GitlabProxyURL,
+		)
+		if err != nil {
+			l.Fatalf("invalid gitlab http client config: %v", err)
+		}
+		gitlabClient := gitlab.NewClient(
+			limiter.NewAdaptiveHTTPDoer(gitlabHTTPClient, conf.GitlabAPIRateLimit),
+			conf.GitlabAPIAddress,
+			conf.GitlabAPIToken,
+		)
+		// Gitlab data is stored in the same db, under separate keys.
+		gitlabStore := database.NewPrefixedKVStore(kvStore, "gitlab/")
+		gitlabCachedClient, closeGitlabClient, err := newCachedForgeClient(gitlabClient, gitlabStore, conf, l, app.ForgeGitlab)
+		if err != nil {
+			l.Fatalf("couldn't create gitlab client: %v", err)
+		}
+		defer closeGitlabClient()
+		forges[app.ForgeGitlab] = gitlabCachedClient
+	}
+
+	if conf.GiteaAPIAddress != "" {
+		giteaHTTPClient, err := newForgeHTTPClient(
+			conf.GiteaHTTPTimeout,
+			conf.GiteaCAFile,
+			conf.GiteaClientCertFile,
+			conf.GiteaClientKeyFile,
+			conf.GiteaProxyURL,
+		)
+		if err != nil {
+			l.Fatalf("invalid gitea http client config: %v", err)
+		}
+		giteaClient, err := gitea.NewClient(
+			limiter.NewAdaptiveHTTPDoer(giteaHTTPClient, conf.GiteaAPIRateLimit),
+			conf.GiteaAPIAddress,
+			conf.GiteaAPIToken,
+			conf.GiteaCommitsMaxPages,
+			l.WithField("component", "giteaClient"),
+		)
+		if err != nil {
+			l.Fatalf("couldn't create gitea client: %v", err)
+		}
+		giteaStore := database.NewPrefixedKVStore(kvStore, "gitea/")
+		giteaCachedClient, closeGiteaClient, err := newCachedForgeClient(giteaClient, giteaStore, conf, l, app.ForgeGitea)
+		if err != nil {
+			l.Fatalf("couldn't create gitea client: %v", err)
+		}
+		defer closeGiteaClient()
+		forges[app.ForgeGitea] = giteaCachedClient
+	}
+
+	loginFilter, err := app.NewLoginFilter(
+		conf.ContributorsExcludeBots,
+		conf.ContributorsAllowList,
+		conf.ContributorsDenyList,
 	)
 	if err != nil {
-		l.Fatalf("couldn't create github client cache: %v", err)
+		l.Fatalf("couldn't create login filter: %v", err)
+	}
+
+	var aliases map[string][]string
+	if conf.ContributorsAliasesFile != "" {
+		aliases, err = identity.LoadMappingFile(conf.ContributorsAliasesFile)
+		if err != nil {
+			l.Fatalf("couldn't load contributors aliases: %v", err)
+		}
+	}
+	identities, err := app.NewIdentityResolver(aliases, identity.NewStore(kvStore))
+	if err != nil {
+		l.Fatalf("couldn't create identity resolver: %v", err)
+	}
+
+	pool, err := app.NewWorkerPool(conf.ServiceWorkers, conf.ServiceWorkerMinTimeLeft)
+	if err != nil {
+		l.Fatalf("couldn't create worker pool: %v", err)
 	}
 
 	service := app.NewService(
-		githubCachedClient,
+		forges,
+		loginFilter,
+		identities,
+		pool,
 		conf.ServiceResponseTimeout,
+		conf.ServiceDegradedResponseMargin,
 	)
 
-	mux := http.NewMux(service, 60*time.Second, l.WithField("component", "mux"))
+	snapshotter := app.NewSnapshotter(
+		service,
+		snapshot.NewStore(kvStore),
+		conf.SnapshotProjectsCount,
+		conf.SnapshotContributorsCount,
+	)
+	for _, lang := range conf.SnapshotLanguages {
+		go runSnapshots(
+			snapshotter,
+			lang,
+			conf.SnapshotInterval,
+			conf.SnapshotRetryInterval,
+			l.WithField("component", "snapshotter"),
+		)
+	}
+
+	mux := http.NewMux(
+		service,
+		snapshotter,
+		identities,
+		conf.AdminAPIToken,
+		60*time.Second,
+		l.WithField("component", "mux"),
+	)
 	server := http.NewServer(
 		conf.HTTPServerAddress,
 		conf.HTTPProfileServerAddress,
@@ -102,3 +282,76 @@ func main() {
 	}()
 	wg.Wait()
 }
+
+// newCachedForgeClient wraps forge client with db and cache layers. Returned func stops db layer's scheduler.
+func newCachedForgeClient(
+	client app.ForgeClient,
+	store forge.KVStore,
+	conf Config,
+	l logrus.FieldLogger,
+	name string,
+) (app.ForgeClient, func(), error) {
+	staleDataClient, err := forge.NewClientWithStaleData(
+		client,
+		store,
+		conf.GithubDBDataTTL,
+		conf.GithubDBDataRefreshTTL,
+		l.WithField("component", name+"StaleDataClient"),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating db client: %w", err)
+	}
+	cachedClient, err := forge.NewCachedClient(
+		staleDataClient,
+		conf.GithubClientCacheSize,
+		conf.GithubClientCacheTTL,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating client cache: %w", err)
+	}
+	staleDataClient.RunScheduler()
+
+	return cachedClient, staleDataClient.Close, nil
+}
+
+// newForgeHTTPClient creates http client for gitlab or gitea api. Connection pool uses default settings,
+// tls and proxy settings are separate from github's.
+func newForgeHTTPClient(
+	timeout time.Duration,
+	caFile string,
+	clientCertFile string,
+	clientKeyFile string,
+	proxyURL string,
+) (forge.HTTPDoer, error) {
+	return forge.NewHTTPClient(forge.HTTPClientConfig{
+		Timeout:             timeout,
+		DialTimeout:         10 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 20,
+		CAFile:              caFile,
+		ClientCertFile:      clientCertFile,
+		ClientKeyFile:       clientKeyFile,
+		ProxyURL:            proxyURL,
+	})
+}
+
+// runSnapshots takes leaderboard snapshot for given language every interval.
+// Failed snapshot is retried after retryInterval.
+func runSnapshots(
+	snapshotter *app.Snapshotter,
+	language string,
+	interval time.Duration,
+	retryInterval time.Duration,
+	l logrus.FieldLogger,
+) {
+	for {
+		wait := interval
+		if _, err := snapshotter.TakeSnapshot(context.Background(), language); err != nil {
+			l.Warnf("taking snapshot for language '%s': %v", language, err)
+			wait = retryInterval
+		}
+		time.Sleep(wait)
+	}
+}
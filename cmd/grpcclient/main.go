@@ -6,16 +6,28 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"strings"
 
 	appGrpc "github.com/m-zajac/goprojectdemo/internal/api/grpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
 	serverAddr    = flag.String("s", "localhost:9090", "The server address in the format of host:port")
+	forge         = flag.String("forge", "github", "Forge hosting the projects: github, gitlab or gitea")
 	language      = flag.String("lang", "go", "Programming language")
 	projectsCount = flag.Int("pc", 5, "Projects count")
 	count         = flag.Int("c", 10, "Results count")
+	rankBy        = flag.String("rank", "commits", "Ranking mode: commits, additions, deletions, net or score")
+	weeks         = flag.Int("weeks", 0, "Count only contributions from last N weeks (0 means all-time)")
+	bestEffort    = flag.Bool("best-effort", false, "Skip projects which stats couldn't be retrieved")
+	minStars      = flag.Int("min-stars", 0, "Minimum number of project's stars")
+	excludeForks  = flag.Bool("no-forks", false, "Exclude forked projects")
+	noArchived    = flag.Bool("no-archived", false, "Exclude archived projects")
+	exclude       = flag.String("exclude", "", "Comma separated login patterns of excluded contributors")
+	login         = flag.String("login", "", "Contributor login. If set, contributor's profile is returned instead of most active contributors")
+	projects      = flag.Bool("projects", false, "If set, top projects are returned instead of most active contributors")
 )
 
 func main() {
@@ -28,19 +40,107 @@ func main() {
 	defer conn.Close()
 	client := appGrpc.NewServiceClient(conn)
 
+	if *login != "" {
+		printProfile(client)
+		return
+	}
+	if *projects {
+		printProjects(client)
+		return
+	}
+
 	req := appGrpc.Request{
+		Forge:         *forge,
 		Language:      *language,
 		ProjectsCount: int32(*projectsCount),
 		Count:         int32(*count),
+		RankBy:        *rankBy,
+		Weeks:         int32(*weeks),
+		BestEffort:    *bestEffort,
+		ProjectFilter: projectFilter(),
+	}
+	if *exclude != "" {
+		req.Exclude = strings.Split(*exclude, ",")
 	}
-	resp, err := client.MostActiveContributors(context.Background(), &req)
+	var header metadata.MD
+	resp, err := client.MostActiveContributors(context.Background(), &req, grpc.Header(&header))
 	if err != nil {
 		log.Fatalf("server response error: %v", err)
 	}
 
-	fmt.Print("   Commits | Login\n")
-	fmt.Print("------------------------\n")
+	fmt.Print("   Commits |  Additions |  Deletions | Login\n")
+	fmt.Print("------------------------------------------------\n")
 	for _, s := range resp.Stat {
-		fmt.Printf("%10d | %s\n", s.Commits, s.Contributor.Login)
+		fmt.Printf("%10d | %10d | %10d | %s\n", s.Commits, s.Additions, s.Deletions, s.Contributor.Login)
+	}
+	if resp.Filtered > 0 {
+		fmt.Printf("\nFiltered out contributors: %d\n", resp.Filtered)
+	}
+	if resp.Incomplete {
+		fmt.Print("\nIncomplete results, skipped projects:\n")
+		for _, s := range resp.Skipped {
+			fmt.Printf("%s/%s (%s)\n", s.Owner, s.Name, s.Reason)
+		}
+	}
+	if v := header.Get(appGrpc.PartialMetadataKey); len(v) > 0 && v[0] == "true" {
+		fmt.Printf("\nPartial results, completeness: %s\n", strings.Join(header.Get(appGrpc.CompletenessMetadataKey), ""))
+	}
+}
+
+func printProfile(client appGrpc.ServiceClient) {
+	req := appGrpc.ProfileRequest{
+		Forge:         *forge,
+		Login:         *login,
+		Language:      *language,
+		ProjectsCount: int32(*projectsCount),
+	}
+	resp, err := client.ContributorProfile(context.Background(), &req)
+	if err != nil {
+		log.Fatalf("server response error: %v", err)
+	}
+
+	fmt.Printf("Login:     %s\n", resp.Contributor.Login)
+	fmt.Printf("Rank:      %d of %d\n", resp.Rank, resp.ContributorsCount)
+	fmt.Printf("Commits:   %d\n", resp.Commits)
+	fmt.Printf("Additions: %d\n", resp.Additions)
+	fmt.Printf("Deletions: %d\n", resp.Deletions)
+	if len(resp.Aliases) > 0 {
+		var logins []string
+		for _, a := range resp.Aliases {
+			logins = append(logins, a.Login)
+		}
+		fmt.Printf("Aliases:   %s\n", strings.Join(logins, ", "))
+	}
+	fmt.Print("\n   Commits | Project\n")
+	fmt.Print("------------------------\n")
+	for _, p := range resp.Projects {
+		fmt.Printf("%10d | %s/%s\n", p.Commits, p.Owner, p.Name)
+	}
+}
+
+func printProjects(client appGrpc.ServiceClient) {
+	req := appGrpc.ProjectsRequest{
+		Forge:         *forge,
+		Language:      *language,
+		Count:         int32(*projectsCount),
+		ProjectFilter: projectFilter(),
+	}
+	resp, err := client.TopProjects(context.Background(), &req)
+	if err != nil {
+		log.Fatalf("server response error: %v", err)
+	}
+
+	fmt.Print("     Stars |      Forks | License      | Project\n")
+	fmt.Print("------------------------------------------------\n")
+	for _, p := range resp.Projects {
+		fmt.Printf("%10d | %10d | %-12s | %s/%s\n", p.Stars, p.Forks, p.License, p.Owner, p.Name)
+	}
+}
+
+func projectFilter() *appGrpc.ProjectFilter {
+	return &appGrpc.ProjectFilter{
+		MinStars:        int32(*minStars),
+		ExcludeForks:    *excludeForks,
+		ExcludeArchived: *noArchived,
 	}
 }
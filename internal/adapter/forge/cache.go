@@ -0,0 +1,176 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/m-zajac/goprojectdemo/internal/singleflight"
+)
+
+// coalescingStats counts executed and shared requests on cache miss. Published under /debug/vars of the profiler server.
+var coalescingStats = expvar.NewMap("forgeCacheCoalescing")
+
+// CachedClient wraps forge client with caching layer.
+// Concurrent requests with the same parameters missing the cache are coalesced into a single request.
+// app.NotFoundError responses are cached too.
+//go:generate mockgen -destination mock/forgecli.go -package mock github.com/m-zajac/goprojectdemo/internal/app ForgeClient
+type CachedClient struct {
+	client        app.ForgeClient
+	projectsCache *lru.Cache
+	statsCache    *lru.Cache
+	ttl           time.Duration
+
+	projectsFlights singleflight.Group
+	statsFlights    singleflight.Group
+}
+
+// NewCachedClient creates new CachedClient instance.
+func NewCachedClient(client app.ForgeClient, size int, ttl time.Duration) (*CachedClient, error) {
+	if size <= 0 {
+		return nil, errors.New("cache size must be greater than 0")
+	}
+	projectsCache, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("creating lru cache for projects: %w", err)
+	}
+	statsCache, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("creating lru cache for stats: %w", err)
+	}
+
+	return &CachedClient{
+		client:        client,
+		projectsCache: projectsCache,
+		statsCache:    statsCache,
+		ttl:           ttl,
+	}, nil
+}
+
+// ProjectsByLanguage returns projects by given programming language name and filters.
+func (c *CachedClient) ProjectsByLanguage(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error) {
+	key := c.projectsCacheKey(q)
+	// Fresh entry with fewer projects is extended with missing ones.
+	var cached projectsCacheEntry
+	val, ok := c.projectsCache.Get(key)
+	if ok {
+		entry := val.(projectsCacheEntry)
+		if entry.created.Add(c.ttl).After(time.Now()) {
+			if entry.err != nil {
+				return nil, entry.err
+			}
+			if entry.count >= count {
+				projects := entry.data
+				if len(projects) > count {
+					projects = projects[:count]
+				}
+				return projects, nil
+			}
+			cached = entry
+		}
+	}
+
+	flightKey := key + "#" + strconv.Itoa(count)
+	v, shared, err := c.projectsFlights.Do(ctx, flightKey, func(ctx context.Context) (interface{}, error) {
+		projects, err := extendProjects(ctx, c.client, q, cached.data, count)
+		if app.IsNotFoundError(err) {
+			c.projectsCache.Add(key, projectsCacheEntry{
+				created: time.Now(),
+				err:     err,
+			})
+		}
+		if err != nil {
+			return projects, err
+		}
+
+		entry := projectsCacheEntry{
+			created: time.Now(),
+			count:   count,
+			data:    projects,
+		}
+		if cached.count > 0 {
+			// Extended entry expires with the original one.
+			entry.created = cached.created
+		}
+		c.projectsCache.Add(key, entry)
+
+		return projects, nil
+	})
+	countFlight("projects", shared)
+	projects, _ := v.([]app.Project)
+
+	return projects, err
+}
+
+// StatsByProject returns stats by given project params.
+func (c *CachedClient) StatsByProject(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+	key := c.statsCacheKey(name, owner)
+	val, ok := c.statsCache.Get(key)
+	if ok {
+		entry := val.(statsCacheEntry)
+		if entry.created.Add(c.ttl).After(time.Now()) {
+			return entry.data, entry.err
+		}
+	}
+
+	v, shared, err := c.statsFlights.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		stats, err := c.client.StatsByProject(ctx, name, owner)
+		if app.IsNotFoundError(err) {
+			c.statsCache.Add(key, statsCacheEntry{
+				created: time.Now(),
+				err:     err,
+			})
+		}
+		if err != nil {
+			return stats, err
+		}
+
+		entry := statsCacheEntry{
+			created: time.Now(),
+			data:    stats,
+		}
+		c.statsCache.Add(key, entry)
+
+		return stats, nil
+	})
+	countFlight("stats", shared)
+	stats, _ := v.([]app.ContributorStats)
+
+	return stats, err
+}
+
+func (c *CachedClient) projectsCacheKey(q app.ProjectsQuery) string {
+	return q.Key()
+}
+
+func (c *CachedClient) statsCacheKey(name string, owner string) string {
+	return name + "/" + owner
+}
+
+func countFlight(name string, shared bool) {
+	if shared {
+		coalescingStats.Add(name+"Shared", 1)
+	} else {
+		coalescingStats.Add(name+"Executed", 1)
+	}
+}
+
+type projectsCacheEntry struct {
+	created time.Time
+	count   int
+	data    []app.Project
+	// err is set for negative entries.
+	err error
+}
+
+type statsCacheEntry struct {
+	created time.Time
+	data    []app.ContributorStats
+	// err is set for negative entries.
+	err error
+}
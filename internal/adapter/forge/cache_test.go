@@ -0,0 +1,356 @@
+package forge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge/mock"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedClientProjectsByLanguage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		cacheSize      int
+		callsWithCount []int
+		callsInterval  time.Duration
+		ttl            time.Duration
+		wantErr        bool
+		wantCalls      int
+	}{
+		{
+			name:      "invalid cache size",
+			cacheSize: 0,
+			wantErr:   true,
+		},
+		{
+			name:           "calls with same parameters",
+			cacheSize:      1,
+			callsWithCount: []int{2, 2, 2, 2},
+			callsInterval:  time.Microsecond,
+			ttl:            time.Minute,
+			wantErr:        false,
+			wantCalls:      1,
+		},
+		{
+			name:           "some calls, then calls with smaller count param",
+			cacheSize:      1,
+			callsWithCount: []int{2, 2, 1, 1},
+			callsInterval:  time.Microsecond,
+			ttl:            time.Minute,
+			wantErr:        false,
+			wantCalls:      1,
+		},
+		{
+			name:           "calls with various count params",
+			cacheSize:      1,
+			callsWithCount: []int{2, 2, 3, 3, 4, 5, 2, 2, 1},
+			callsInterval:  time.Microsecond,
+			ttl:            time.Minute,
+			wantErr:        false,
+			wantCalls:      4,
+		},
+		{
+			name:           "calls with expiring ttl",
+			cacheSize:      1,
+			callsWithCount: []int{2, 2, 2, 2},
+			callsInterval:  5 * time.Millisecond,
+			ttl:            time.Millisecond,
+			wantErr:        false,
+			wantCalls:      4,
+		},
+	}
+
+	projectsResponse := []app.Project{
+		{
+			ID:         1,
+			Name:       "project1",
+			OwnerLogin: "owner1",
+		},
+		{
+			ID:         2,
+			Name:       "project2",
+			OwnerLogin: "owner2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var clientCalls int
+
+			client := mock.NewMockForgeClient(ctrl)
+			client.EXPECT().
+				ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error) {
+					clientCalls++
+					return projectsResponse, nil
+				}).
+				AnyTimes()
+
+			cachedClient, err := NewCachedClient(client, tt.cacheSize, tt.ttl)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err != nil {
+				return
+			}
+
+			for _, count := range tt.callsWithCount {
+				projects, err := cachedClient.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, count)
+				require.NoError(t, err)
+				require.Equal(t, projectsResponse[0], projects[0])
+				time.Sleep(tt.callsInterval)
+			}
+
+			assert.Equal(t, tt.wantCalls, clientCalls)
+		})
+	}
+}
+
+func TestCachedClientStatsByProject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		cacheSize     int
+		calls         int
+		callsInterval time.Duration
+		ttl           time.Duration
+		wantErr       bool
+		wantCalls     int
+	}{
+		{
+			name:      "invalid cache size",
+			cacheSize: 0,
+			wantErr:   true,
+		},
+		{
+			name:          "calls with same parameters",
+			cacheSize:     1,
+			calls:         4,
+			callsInterval: time.Microsecond,
+			ttl:           time.Minute,
+			wantErr:       false,
+			wantCalls:     1,
+		},
+		{
+			name:          "calls with expiring ttl",
+			cacheSize:     1,
+			calls:         4,
+			callsInterval: 5 * time.Millisecond,
+			ttl:           time.Millisecond,
+			wantErr:       false,
+			wantCalls:     4,
+		},
+	}
+
+	statsResponse := []app.ContributorStats{
+		{
+			Contributor: app.Contributor{
+				ID:    1,
+				Login: "person1",
+			},
+			Commits: 10,
+		},
+		{
+			Contributor: app.Contributor{
+				ID:    2,
+				Login: "person2",
+			},
+			Commits: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var clientCalls int
+
+			client := mock.NewMockForgeClient(ctrl)
+			client.EXPECT().
+				StatsByProject(gomock.Any(), "go", "golang").
+				DoAndReturn(func(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+					clientCalls++
+					return statsResponse, nil
+				}).
+				AnyTimes()
+
+			cachedClient, err := NewCachedClient(client, tt.cacheSize, tt.ttl)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err != nil {
+				return
+			}
+
+			for i := 0; i < tt.calls; i++ {
+				stats, err := cachedClient.StatsByProject(context.Background(), "go", "golang")
+				require.NoError(t, err)
+				require.Equal(t, statsResponse[0], stats[0])
+				time.Sleep(tt.callsInterval)
+			}
+
+			assert.Equal(t, tt.wantCalls, clientCalls)
+		})
+	}
+}
+
+func TestCachedClientProjectsByLanguageFilters(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filteredQuery := app.ProjectsQuery{
+		Language: "go",
+		Filter: app.ProjectFilter{
+			MinStars: 100,
+			Topics:   []string{"cli", "http"},
+		},
+	}
+	sameFilteredQuery := app.ProjectsQuery{
+		Language: "go",
+		Filter: app.ProjectFilter{
+			MinStars: 100,
+			Topics:   []string{"http", "cli"},
+		},
+	}
+
+	client := mock.NewMockForgeClient(ctrl)
+	client.EXPECT().
+		ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 1).
+		Return([]app.Project{{ID: 1}}, nil)
+	client.EXPECT().
+		ProjectsByLanguage(gomock.Any(), filteredQuery, 1).
+		Return([]app.Project{{ID: 2}}, nil)
+
+	cachedClient, err := NewCachedClient(client, 10, time.Minute)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		query  app.ProjectsQuery
+		wantID int
+	}{
+		{query: app.ProjectsQuery{Language: "go"}, wantID: 1},
+		{query: filteredQuery, wantID: 2},
+		{query: app.ProjectsQuery{Language: "go"}, wantID: 1},
+		{query: sameFilteredQuery, wantID: 2},
+	} {
+		projects, err := cachedClient.ProjectsByLanguage(context.Background(), tt.query, 1)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		assert.Equal(t, tt.wantID, projects[0].ID)
+	}
+}
+
+func TestCachedClientCoalescesConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statsResponse := []app.ContributorStats{
+		{
+			Contributor: app.Contributor{
+				ID:    1,
+				Login: "person1",
+			},
+			Commits: 10,
+		},
+	}
+
+	requested := make(chan struct{})
+	release := make(chan struct{})
+	client := mock.NewMockForgeClient(ctrl)
+	client.EXPECT().
+		StatsByProject(gomock.Any(), "go", "golang").
+		DoAndReturn(func(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+			close(requested)
+			<-release
+			return statsResponse, nil
+		}).
+		Times(1)
+
+	cachedClient, err := NewCachedClient(client, 1, time.Minute)
+	require.NoError(t, err)
+
+	const callers = 5
+	results := make(chan []app.ContributorStats, callers)
+	call := func() {
+		stats, err := cachedClient.StatsByProject(context.Background(), "go", "golang")
+		assert.NoError(t, err)
+		results <- stats
+	}
+
+	go call()
+	<-requested
+	for i := 1; i < callers; i++ {
+		go call()
+	}
+	// Give other callers time to join the request.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < callers; i++ {
+		assert.Equal(t, statsResponse, <-results)
+	}
+}
+
+func TestCachedClientExtendsProjectsPageByPage(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeProjectsPager(500)
+	cachedClient, err := NewCachedClient(client, 10, time.Minute)
+	require.NoError(t, err)
+
+	q := app.ProjectsQuery{Language: "go"}
+	for _, count := range []int{150, 300, 200, 300} {
+		projects, err := cachedClient.ProjectsByLanguage(context.Background(), q, count)
+		require.NoError(t, err)
+		require.Len(t, projects, count)
+		for i, p := range projects {
+			require.Equal(t, i+1, p.ID)
+		}
+	}
+
+	assert.Equal(t, []int{150}, client.searches)
+	assert.Equal(t, []int{2, 3}, client.pages)
+}
+
+func TestCachedClientCachesNotFound(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock.NewMockForgeClient(ctrl)
+	client.EXPECT().
+		ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "nolang"}, 10).
+		Return(nil, app.NotFoundError("not found")).
+		Times(1)
+	client.EXPECT().
+		StatsByProject(gomock.Any(), "go", "golang").
+		Return(nil, app.NotFoundError("not found")).
+		Times(1)
+
+	cachedClient, err := NewCachedClient(client, 10, time.Minute)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := cachedClient.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "nolang"}, 10)
+		assert.True(t, app.IsNotFoundError(err))
+		// Not found entry is valid for any count.
+		_, err = cachedClient.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "nolang"}, 20)
+		assert.True(t, app.IsNotFoundError(err))
+
+		_, err = cachedClient.StatsByProject(context.Background(), "go", "golang")
+		assert.True(t, app.IsNotFoundError(err))
+	}
+}
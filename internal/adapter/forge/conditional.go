@@ -0,0 +1,32 @@
+package forge
+
+import (
+	"context"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+// Validators identify version of a resource retrieved from the api. They are sent with conditional requests,
+// so the api can respond with status 304 if the resource didn't change.
+// See: https://docs.github.com/en/rest/overview/resources-in-the-rest-api#conditional-requests
+type Validators struct {
+	ETag         string `json:",omitempty"`
+	LastModified string `json:",omitempty"`
+}
+
+// Empty tells if there are no validators.
+func (v Validators) Empty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// ConditionalStatsClient returns project's stats only if they changed since they were retrieved.
+type ConditionalStatsClient interface {
+	// StatsByProjectIfModified returns stats and their validators, if stats changed since version identified by v.
+	// If stats didn't change, modified is false and no stats are returned.
+	StatsByProjectIfModified(
+		ctx context.Context,
+		name string,
+		owner string,
+		v Validators,
+	) (stats []app.ContributorStats, newV Validators, modified bool, err error)
+}
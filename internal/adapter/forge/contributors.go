@@ -0,0 +1,75 @@
+package forge
+
+import (
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WeekStart returns beginning of the week containing t. Weeks start on Sunday, midnight UTC, as in github's stats.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+// maxInt is the maximum value of int.
+const maxInt = int(^uint(0) >> 1)
+
+// EmailIDs assigns ids to commit authors identified only by email, like gitlab contributors or authors of gitea
+// commits without linked account. Emails are case insensitive. Ids are negative, so they don't collide with ids
+// of forge accounts.
+//
+// Id is derived from email's hash, so it's the same after restart. Distinct emails always get distinct ids,
+// stats of different authors are never merged: if hashes collide, email seen later gets the next free id.
+type EmailIDs struct {
+	hash func(email string) int
+
+	m      sync.Mutex
+	ids    map[string]int
+	emails map[int]string
+}
+
+// NewEmailIDs creates EmailIDs instance.
+func NewEmailIDs() *EmailIDs {
+	return &EmailIDs{
+		hash:   emailHash,
+		ids:    make(map[string]int),
+		emails: make(map[int]string),
+	}
+}
+
+// ID returns id of author with given email.
+func (e *EmailIDs) ID(email string) int {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	if id, ok := e.ids[email]; ok {
+		return id
+	}
+	id := e.hash(email)
+	for {
+		if _, taken := e.emails[id]; !taken {
+			break
+		}
+		if id == -maxInt-1 {
+			id = -1
+		} else {
+			id--
+		}
+	}
+	e.ids[email] = id
+	e.emails[id] = email
+
+	return id
+}
+
+// emailHash returns negative id derived from email's hash.
+func emailHash(email string) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(email))
+	return -int(h.Sum64()&uint64(maxInt)) - 1
+}
@@ -0,0 +1,77 @@
+package forge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekStart(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "sunday midnight",
+			t:    time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "saturday evening",
+			t:    time.Date(2020, 5, 2, 23, 59, 59, 0, time.UTC),
+			want: time.Date(2020, 4, 26, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "other time zone",
+			t:    time.Date(2020, 5, 3, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			want: time.Date(2020, 4, 26, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WeekStart(tt.t))
+		})
+	}
+}
+
+func TestEmailIDs(t *testing.T) {
+	t.Parallel()
+
+	ids := NewEmailIDs()
+	jane := ids.ID("jane@example.com")
+	assert.True(t, jane < 0)
+	assert.Equal(t, jane, ids.ID(" JANE@example.com"))
+	assert.NotEqual(t, jane, ids.ID("john@example.com"))
+
+	// Ids are derived from emails, so they don't depend on the order of calls.
+	assert.Equal(t, jane, NewEmailIDs().ID("jane@example.com"))
+}
+
+func TestEmailIDsCollision(t *testing.T) {
+	t.Parallel()
+
+	ids := NewEmailIDs()
+	ids.hash = func(email string) int {
+		if email == "last@example.com" {
+			return -maxInt - 1
+		}
+		return -10
+	}
+
+	jane := ids.ID("jane@example.com")
+	john := ids.ID("john@example.com")
+	bob := ids.ID("bob@example.com")
+	assert.Equal(t, -10, jane)
+	assert.Equal(t, -11, john)
+	assert.Equal(t, -12, bob)
+	assert.Equal(t, jane, ids.ID("jane@example.com"))
+	assert.Equal(t, john, ids.ID("john@example.com"))
+
+	// The lowest id wraps around to -1.
+	ids.emails[-maxInt-1] = "taken@example.com"
+	assert.Equal(t, -1, ids.ID("last@example.com"))
+}
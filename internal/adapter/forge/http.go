@@ -0,0 +1,102 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+// HTTPDoer can execute http request.
+type HTTPDoer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Response holds details of api response.
+type Response struct {
+	Body   []byte
+	Status int
+	Header http.Header
+}
+
+// Do executes http request and reads up to maxBytes of response's body.
+// Response body is always drained before close to allow connection reuse.
+// Response status is not checked, see StatusError.
+func Do(ctx context.Context, doer HTTPDoer, req *http.Request, maxBytes int) (*Response, error) {
+	resp, err := doer.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("doing http request: %w", err)
+	}
+	// See: http://tleyden.github.io/blog/2016/11/21/tuning-the-go-http-client-library-for-load-testing/
+	defer func() {
+		_, _ = io.CopyN(ioutil.Discard, resp.Body, 1024)
+		resp.Body.Close()
+	}()
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("reading http response body: %w", err)
+	}
+
+	return &Response{
+		Body:   b,
+		Status: resp.StatusCode,
+		Header: resp.Header,
+	}, nil
+}
+
+// StatusError returns error for response with status other than 2xx. Returns nil for successful responses.
+// Status 404 is app.NotFoundError, and status 429 is app.RateLimitError.
+func StatusError(req *http.Request, resp *Response) error {
+	switch {
+	case resp.Status/100 == 2:
+		return nil
+	case resp.Status == http.StatusNotFound:
+		return app.NotFoundError(fmt.Sprintf("resource %s not found", req.URL.Path))
+	case resp.Status == http.StatusTooManyRequests:
+		return app.RateLimitError{
+			RetryAfter: RetryAfter(resp.Header),
+		}
+	default:
+		return fmt.Errorf("got invalid http status code: %d", resp.Status)
+	}
+}
+
+// RetryAfter returns the time after which rate limited request can be retried, from Retry-After header,
+// or from RateLimit-Reset header with unix time of limit's reset. Returns 0 if it's unknown.
+func RetryAfter(h http.Header) time.Duration {
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if reset, err := strconv.ParseInt(h.Get("RateLimit-Reset"), 10, 64); err == nil {
+		retryAfter = time.Until(time.Unix(reset, 0))
+	}
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+
+	return retryAfter
+}
+
+// NextPageURL returns url of the next page from response's Link header, or empty string if there's no next page.
+// See: https://datatracker.ietf.org/doc/html/rfc8288
+func NextPageURL(h http.Header) string {
+	for _, link := range strings.Split(h.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+		for _, param := range parts[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package forge
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		status         int
+		header         http.Header
+		wantErr        bool
+		wantNotFound   bool
+		wantRetryAfter time.Duration
+		wantRateLimit  bool
+	}{
+		{
+			name:   "ok",
+			status: http.StatusOK,
+		},
+		{
+			name:   "accepted",
+			status: http.StatusAccepted,
+		},
+		{
+			name:         "not found",
+			status:       http.StatusNotFound,
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name:           "rate limited with retry after",
+			status:         http.StatusTooManyRequests,
+			header:         http.Header{"Retry-After": []string{"30"}},
+			wantErr:        true,
+			wantRateLimit:  true,
+			wantRetryAfter: 30 * time.Second,
+		},
+		{
+			name:          "rate limited with reset in the past",
+			status:        http.StatusTooManyRequests,
+			header:        http.Header{"Ratelimit-Reset": []string{"1"}},
+			wantErr:       true,
+			wantRateLimit: true,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := http.NewRequest(http.MethodGet, "http://forge/api/resource", nil)
+			err := StatusError(req, &Response{Status: tt.status, Header: tt.header})
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+
+			var notFound app.NotFoundError
+			assert.Equal(t, tt.wantNotFound, errors.As(err, &notFound))
+
+			var rateLimit app.RateLimitError
+			assert.Equal(t, tt.wantRateLimit, errors.As(err, &rateLimit))
+			assert.Equal(t, tt.wantRetryAfter, rateLimit.RetryAfter)
+		})
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "no header",
+		},
+		{
+			name: "next and last",
+			link: `<http://forge/api?page=2>; rel="next", <http://forge/api?page=5>; rel="last"`,
+			want: "http://forge/api?page=2",
+		},
+		{
+			name: "last page",
+			link: `<http://forge/api?page=1>; rel="first", <http://forge/api?page=4>; rel="prev"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := make(http.Header)
+			if tt.link != "" {
+				h.Set("Link", tt.link)
+			}
+			assert.Equal(t, tt.want, NextPageURL(h))
+		})
+	}
+}
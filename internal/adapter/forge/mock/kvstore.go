@@ -5,7 +5,7 @@ import (
 	"time"
 )
 
-// KVStore mocks github.KVStore.
+// KVStore mocks forge.KVStore.
 type KVStore struct {
 	data        map[string][]byte
 	reads       int
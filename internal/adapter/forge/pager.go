@@ -0,0 +1,74 @@
+package forge
+
+import (
+	"context"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+const (
+	// ProjectsPageSize is the number of projects in a single page returned by ProjectsPager.
+	ProjectsPageSize = 100
+	// ProjectsMaxResults is the maximum number of projects ProjectsPager can return for a single query.
+	ProjectsMaxResults = 1000
+)
+
+// ProjectsPager returns projects search results page by page.
+type ProjectsPager interface {
+	// ProjectsPage returns given page of projects. Pages are numbered from 1 and hold ProjectsPageSize projects.
+	// hasNext tells if there are more pages.
+	ProjectsPage(ctx context.Context, q app.ProjectsQuery, page int) (projects []app.Project, hasNext bool, err error)
+}
+
+// extendProjects returns `count` projects, where first ones are already retrieved `projects`.
+// If client is a ProjectsPager, only missing pages are retrieved. Otherwise all projects are retrieved again.
+// Returned slice is never the one given, so it can be modified safely.
+func extendProjects(
+	ctx context.Context,
+	client app.ForgeClient,
+	q app.ProjectsQuery,
+	projects []app.Project,
+	count int,
+) ([]app.Project, error) {
+	pager, ok := client.(ProjectsPager)
+	if !ok || len(projects) == 0 {
+		return client.ProjectsByLanguage(ctx, q, count)
+	}
+
+	extended := append(make([]app.Project, 0, count), projects...)
+	ids := make(map[int]bool, count)
+	for _, p := range projects {
+		ids[p.ID] = true
+	}
+
+	for len(extended) < count {
+		pageNum := len(extended)/ProjectsPageSize + 1
+		if pageNum > ProjectsMaxResults/ProjectsPageSize {
+			break
+		}
+		page, hasNext, err := pager.ProjectsPage(ctx, q, pageNum)
+		if err != nil {
+			return nil, err
+		}
+
+		var added int
+		// Projects on the page before the offset are already retrieved. Ranking could change in the meantime,
+		// so duplicates are skipped too.
+		for i := len(extended) % ProjectsPageSize; i < len(page); i++ {
+			if ids[page[i].ID] {
+				continue
+			}
+			ids[page[i].ID] = true
+			extended = append(extended, page[i])
+			added++
+		}
+		if !hasNext || added == 0 {
+			break
+		}
+	}
+	if len(extended) > count {
+		extended = extended[:count]
+	}
+
+	return extended, nil
+}
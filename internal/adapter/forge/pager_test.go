@@ -0,0 +1,141 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendProjects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		total      int
+		cached     int
+		count      int
+		pageErr    error
+		wantIDs    int
+		wantPages  []int
+		wantSearch []int
+		wantErr    bool
+	}{
+		{
+			name:       "nothing cached",
+			total:      500,
+			count:      150,
+			wantIDs:    150,
+			wantSearch: []int{150},
+		},
+		{
+			name:      "extend from the middle of the page",
+			total:     500,
+			cached:    150,
+			count:     300,
+			wantIDs:   300,
+			wantPages: []int{2, 3},
+		},
+		{
+			name:      "extend from the page boundary",
+			total:     500,
+			cached:    100,
+			count:     150,
+			wantIDs:   150,
+			wantPages: []int{2},
+		},
+		{
+			name:      "not enough projects",
+			total:     220,
+			cached:    150,
+			count:     400,
+			wantIDs:   220,
+			wantPages: []int{2, 3},
+		},
+		{
+			name:      "page error",
+			total:     500,
+			cached:    50,
+			count:     150,
+			pageErr:   errors.New("error"),
+			wantPages: []int{1},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeProjectsPager(tt.total)
+			client.pageErr = tt.pageErr
+
+			var cached []app.Project
+			if tt.cached > 0 {
+				cached = client.projects[:tt.cached]
+			}
+			got, err := extendProjects(context.Background(), client, app.ProjectsQuery{Language: "go"}, cached, tt.count)
+			require.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantPages, client.pages)
+			assert.Equal(t, tt.wantSearch, client.searches)
+			if tt.wantErr {
+				return
+			}
+
+			require.Len(t, got, tt.wantIDs)
+			for i, p := range got {
+				require.Equal(t, i+1, p.ID)
+			}
+		})
+	}
+}
+
+// fakeProjectsPager returns projects with consecutive IDs, starting from 1.
+type fakeProjectsPager struct {
+	projects []app.Project
+	pageErr  error
+
+	m        sync.Mutex
+	searches []int
+	pages    []int
+}
+
+func newFakeProjectsPager(total int) *fakeProjectsPager {
+	var c fakeProjectsPager
+	for i := 1; i <= total; i++ {
+		c.projects = append(c.projects, app.Project{ID: i})
+	}
+	return &c
+}
+
+func (c *fakeProjectsPager) ProjectsByLanguage(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.searches = append(c.searches, count)
+	if count > len(c.projects) {
+		count = len(c.projects)
+	}
+	return append([]app.Project{}, c.projects[:count]...), nil
+}
+
+func (c *fakeProjectsPager) ProjectsPage(ctx context.Context, q app.ProjectsQuery, page int) ([]app.Project, bool, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.pages = append(c.pages, page)
+	if c.pageErr != nil {
+		return nil, false, c.pageErr
+	}
+	start := (page - 1) * ProjectsPageSize
+	end := start + ProjectsPageSize
+	if end > len(c.projects) {
+		end = len(c.projects)
+	}
+	return append([]app.Project{}, c.projects[start:end]...), end < len(c.projects), nil
+}
+
+func (c *fakeProjectsPager) StatsByProject(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+	return nil, nil
+}
@@ -0,0 +1,539 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/sirupsen/logrus"
+)
+
+// Resources rate limited by upstream api separately, like github limits search requests apart from other requests.
+const (
+	projectsResource = "projects"
+	statsResource    = "stats"
+)
+
+// notModifiedStats counts stats updates confirmed as up to date by conditional requests.
+var notModifiedStats = expvar.NewInt("forgeStatsNotModified")
+
+// KVStore provides simple kv data storage
+type KVStore interface {
+	ReadKey(key []byte) ([]byte, error)
+	UpdateKey(key []byte, data []byte) error
+}
+
+// ClientWithStaleData wraps ForgeClient and returns data saved in db if possible.
+//
+// If data is not available (or datas ttl is exceeded), update is scheduled, and app.ScheduledForLaterError is returned with empty data.
+// If data is available, ttl is ok, but refreshTTL is exceeded, additional job for update is scheduled. Exisiting data is returned immediately.
+// If data is available and no ttl is exceeded, then data is returned immediately.
+// Missing resources are saved too, app.NotFoundError is returned for them until ttl is exceeded.
+// Stats being computed by upstream api are polled in the background, with exponential backoff.
+// When upstream api reports exceeded rate limit, app.RateLimitError is returned for missing data of the same resource
+// until the limit resets, instead of scheduling more updates.
+type ClientWithStaleData struct {
+	client     app.ForgeClient
+	store      KVStore
+	ttl        time.Duration
+	refreshTTL time.Duration
+	l          logrus.FieldLogger
+
+	projectUpdates chan projectsDBUpdateRequest
+	statsUpdates   chan statsDBUpdateRequest
+
+	// computingBackoff is the initial delay of polling stats being computed. It's doubled after every poll.
+	computingBackoff time.Duration
+	// computingMaxBackoff is the maximum delay of polling stats being computed.
+	computingMaxBackoff time.Duration
+	// computingMaxPolls is the maximum number of polls of stats being computed.
+	computingMaxPolls int
+
+	// rateLimits holds time until which upstream api's resource is rate limited, by resource.
+	rateLimits map[string]rateLimit
+	m          sync.Mutex
+
+	// Chan for controlling scheduler - only used for unit testing.
+	schedulerPendingOps chan int
+
+	// Func for canceling internal worker loop and initializing db cleanup
+	stop func()
+}
+
+// NewClientWithStaleData creates new ClientWithStaleData instance.
+func NewClientWithStaleData(
+	client app.ForgeClient,
+	store KVStore,
+	ttl time.Duration,
+	refreshTTL time.Duration,
+	l logrus.FieldLogger,
+) (*ClientWithStaleData, error) {
+	c := ClientWithStaleData{
+		client:         client,
+		store:          store,
+		ttl:            ttl,
+		refreshTTL:     refreshTTL,
+		l:              l,
+		projectUpdates: make(chan projectsDBUpdateRequest, 1000),
+		statsUpdates:   make(chan statsDBUpdateRequest, 1000),
+
+		computingBackoff:    2 * time.Second,
+		computingMaxBackoff: time.Minute,
+		computingMaxPolls:   10,
+
+		rateLimits: make(map[string]rateLimit),
+	}
+
+	return &c, nil
+}
+
+// RunScheduler runs internal scheduling goroutine.
+// Doesn't block.
+func (c *ClientWithStaleData) RunScheduler() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stop = cancel
+
+	go func() {
+		pendingProjectUpdates := make(map[string]bool)
+		pendingStatsUpdates := make(map[string]bool)
+
+		doneProjectUpdates := make(chan string)
+		doneStatsUpdates := make(chan string)
+
+		for {
+			// This is intended for blocking scheduler for unit testing.
+			// In standard execution this is always nil.
+			if c.schedulerPendingOps != nil {
+				c.schedulerPendingOps <- len(pendingProjectUpdates) + len(pendingStatsUpdates)
+			}
+
+			select {
+			// Projects
+			case req := <-c.projectUpdates:
+				key := req.query.Key()
+				if pendingProjectUpdates[key] {
+					continue
+				}
+				pendingProjectUpdates[key] = true
+
+				go func(req projectsDBUpdateRequest) {
+					c.l.Infof("ClientWithStaleData: scheduled projects update for %s...", key)
+					if err := c.updateProjects(req); err != nil {
+						c.l.Errorf("ClientWithStaleData scheduler: updating projects data: %v", err)
+					} else {
+						c.l.Infof("ClientWithStaleData: scheduled projects update for %s done", key)
+					}
+					doneProjectUpdates <- key
+				}(req)
+			case key := <-doneProjectUpdates:
+				delete(pendingProjectUpdates, key)
+
+			// Stats
+			case req := <-c.statsUpdates:
+				key := fmt.Sprintf("%s/%s", req.owner, req.name)
+				if pendingStatsUpdates[key] {
+					continue
+				}
+				pendingStatsUpdates[key] = true
+
+				go func(req statsDBUpdateRequest) {
+					c.l.Infof("ClientWithStaleData: scheduled stats update for %s/%s...", req.owner, req.name)
+					if err := c.pollStats(ctx, req); err != nil {
+						c.l.Errorf("ClientWithStaleData scheduler: updating stats data: %v", err)
+					} else {
+						c.l.Infof("ClientWithStaleData: scheduled stats update for %s/%s done", req.owner, req.name)
+					}
+					select {
+					case doneStatsUpdates <- key:
+					case <-ctx.Done():
+					}
+				}(req)
+			case key := <-doneStatsUpdates:
+				delete(pendingStatsUpdates, key)
+
+			// Finish
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// ProjectsByLanguage returns projects by given programming language name and filters.
+//
+// Returns data from db if available.
+func (c *ClientWithStaleData) ProjectsByLanguage(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error) {
+	key := c.projectsDBKey(q)
+	data, err := c.store.ReadKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var base *projectsDBEntry
+	if data != nil {
+		entry, err := c.unserializeProjects(data)
+		if err != nil {
+			return nil, fmt.Errorf("unserializing projects data: %w", err)
+		}
+		entryCreated := time.Unix(entry.Created, 0)
+		fresh := entryCreated.Add(c.ttl).After(time.Now())
+		if fresh && entry.NotFound {
+			return nil, app.NotFoundError(fmt.Sprintf("no projects found for query %s", q.Key()))
+		}
+		if fresh && entry.Count < count {
+			// Entry with fewer projects is extended with missing ones.
+			base = entry
+		}
+		if fresh && entry.Count >= count {
+			if entryCreated.Add(c.refreshTTL).Before(time.Now()) && c.activeRateLimit(projectsResource) == nil {
+				go func() {
+					c.projectUpdates <- projectsDBUpdateRequest{
+						query: q,
+						count: count,
+					}
+				}()
+			}
+
+			projects := entry.Data
+			if len(projects) > count {
+				projects = projects[:count]
+			}
+			return projects, nil
+		}
+	}
+
+	if err := c.activeRateLimit(projectsResource); err != nil {
+		return nil, err
+	}
+
+	select {
+	case c.projectUpdates <- projectsDBUpdateRequest{
+		query: q,
+		count: count,
+		base:  base,
+	}:
+		return nil, app.ScheduledForLaterError("scheduled")
+	default:
+		return nil, errors.New("stale data scheduler: no free slots left")
+	}
+}
+
+// StatsByProject returns stats by given project params.
+//
+// Returns data from db if available.
+func (c *ClientWithStaleData) StatsByProject(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+	key := c.statsDBKey(name, owner)
+	data, err := c.store.ReadKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var current *statsDBEntry
+	if data != nil {
+		entry, err := c.unserializeStats(data)
+		if err != nil {
+			return nil, fmt.Errorf("unserializing stats data: %w", err)
+		}
+		// Even expired entry can be confirmed as up to date by conditional request.
+		current = entry
+		entryCreated := time.Unix(entry.Created, 0)
+		if entryCreated.Add(c.ttl).After(time.Now()) {
+			if entry.NotFound {
+				return nil, app.NotFoundError(fmt.Sprintf("project %s/%s not found", owner, name))
+			}
+			if entryCreated.Add(c.refreshTTL).Before(time.Now()) && c.activeRateLimit(statsResource) == nil {
+				c.statsUpdates <- statsDBUpdateRequest{
+					name:    name,
+					owner:   owner,
+					current: entry,
+				}
+			}
+
+			return entry.Data, nil
+		}
+	}
+
+	if err := c.activeRateLimit(statsResource); err != nil {
+		return nil, err
+	}
+
+	select {
+	case c.statsUpdates <- statsDBUpdateRequest{
+		name:    name,
+		owner:   owner,
+		current: current,
+	}:
+		return nil, app.ScheduledForLaterError("scheduled")
+	default:
+		return nil, errors.New("stale data scheduler: no free slots left")
+	}
+}
+
+// Close cleanups scheduler and closes underlying database.
+func (c *ClientWithStaleData) Close() {
+	if c.stop != nil {
+		c.stop()
+		c.stop = nil
+	}
+}
+
+func (c *ClientWithStaleData) updateProjects(req projectsDBUpdateRequest) error {
+	var base []app.Project
+	created := time.Now()
+	if req.base != nil {
+		base = req.base.Data
+		// Extended entry expires with the original one.
+		created = time.Unix(req.base.Created, 0)
+	}
+	projects, err := extendProjects(context.Background(), c.client, req.query, base, req.count)
+	if app.IsNotFoundError(err) {
+		if err := c.saveProjectsNotFound(req.query); err != nil {
+			return fmt.Errorf("saving projects: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		c.recordRateLimit(projectsResource, err)
+		return fmt.Errorf("calling client.ProjectsByLanguage: %w", err)
+	}
+	if err := c.saveProjects(req.query, req.count, projects, created); err != nil {
+		return fmt.Errorf("saving projects: %w", err)
+	}
+
+	return nil
+}
+
+func (c *ClientWithStaleData) updateStats(req statsDBUpdateRequest) error {
+	stats, v, err := c.fetchStats(req)
+	if app.IsNotFoundError(err) {
+		if err := c.saveStatsNotFound(req.name, req.owner); err != nil {
+			return fmt.Errorf("saving stats: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		c.recordRateLimit(statsResource, err)
+		return fmt.Errorf("calling client.StatsByProject: %w", err)
+	}
+	if err := c.saveStats(req.name, req.owner, stats, v); err != nil {
+		return fmt.Errorf("saving stats: %w", err)
+	}
+
+	return nil
+}
+
+// pollStats updates stats, until they're no longer being computed by upstream api.
+func (c *ClientWithStaleData) pollStats(ctx context.Context, req statsDBUpdateRequest) error {
+	backoff := c.computingBackoff
+	for polls := 1; ; polls++ {
+		err := c.updateStats(req)
+		if !app.IsUpstreamComputingError(err) || polls >= c.computingMaxPolls {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > c.computingMaxBackoff {
+			backoff = c.computingMaxBackoff
+		}
+	}
+}
+
+// fetchStats returns project's stats with their validators.
+// If client supports conditional requests and stats didn't change since current entry was saved, entry's data is returned.
+func (c *ClientWithStaleData) fetchStats(req statsDBUpdateRequest) ([]app.ContributorStats, Validators, error) {
+	conditionalClient, ok := c.client.(ConditionalStatsClient)
+	if !ok {
+		stats, err := c.client.StatsByProject(context.Background(), req.name, req.owner)
+		return stats, Validators{}, err
+	}
+
+	var v Validators
+	if req.current != nil {
+		v = req.current.Validators
+	}
+	stats, newV, modified, err := conditionalClient.StatsByProjectIfModified(context.Background(), req.name, req.owner, v)
+	if err != nil {
+		return nil, Validators{}, err
+	}
+	if !modified && req.current != nil {
+		notModifiedStats.Add(1)
+		return req.current.Data, v, nil
+	}
+
+	return stats, newV, nil
+}
+
+// recordRateLimit saves rate limit of given resource, if err is app.RateLimitError.
+func (c *ClientWithStaleData) recordRateLimit(resource string, err error) {
+	var rateLimitErr app.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.rateLimits[resource] = rateLimit{
+		until:     time.Now().Add(rateLimitErr.RetryAfter),
+		exhausted: rateLimitErr.Exhausted,
+	}
+}
+
+// activeRateLimit returns app.RateLimitError if given resource is still rate limited. Otherwise returns nil.
+func (c *ClientWithStaleData) activeRateLimit(resource string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	limit, ok := c.rateLimits[resource]
+	if !ok {
+		return nil
+	}
+	retryAfter := time.Until(limit.until)
+	if retryAfter <= 0 {
+		delete(c.rateLimits, resource)
+		return nil
+	}
+
+	return app.RateLimitError{
+		RetryAfter: retryAfter,
+		Exhausted:  limit.exhausted,
+	}
+}
+
+func (c *ClientWithStaleData) saveProjects(
+	q app.ProjectsQuery,
+	count int,
+	projects []app.Project,
+	created time.Time,
+) error {
+	dbdata, err := c.serializeProjects(projectsDBEntry{
+		Created: created.Unix(),
+		Count:   count,
+		Data:    projects,
+	})
+	if err != nil {
+		return fmt.Errorf("serializing data for save: %w", err)
+	}
+
+	return c.store.UpdateKey(c.projectsDBKey(q), dbdata)
+}
+
+func (c *ClientWithStaleData) saveStats(name string, owner string, stats []app.ContributorStats, v Validators) error {
+	dbdata, err := c.serializeStats(statsDBEntry{
+		Created:    time.Now().Unix(),
+		Data:       stats,
+		Validators: v,
+	})
+	if err != nil {
+		return fmt.Errorf("serializing data for save: %w", err)
+	}
+
+	return c.store.UpdateKey(c.statsDBKey(name, owner), dbdata)
+}
+
+func (c *ClientWithStaleData) saveProjectsNotFound(q app.ProjectsQuery) error {
+	dbdata, err := c.serializeProjects(projectsDBEntry{
+		Created:  time.Now().Unix(),
+		NotFound: true,
+	})
+	if err != nil {
+		return fmt.Errorf("serializing data for save: %w", err)
+	}
+
+	return c.store.UpdateKey(c.projectsDBKey(q), dbdata)
+}
+
+func (c *ClientWithStaleData) saveStatsNotFound(name string, owner string) error {
+	dbdata, err := c.serializeStats(statsDBEntry{
+		Created:  time.Now().Unix(),
+		NotFound: true,
+	})
+	if err != nil {
+		return fmt.Errorf("serializing data for save: %w", err)
+	}
+
+	return c.store.UpdateKey(c.statsDBKey(name, owner), dbdata)
+}
+
+func (c *ClientWithStaleData) projectsDBKey(q app.ProjectsQuery) []byte {
+	return []byte("pr/" + q.Key())
+}
+
+func (c *ClientWithStaleData) statsDBKey(name string, owner string) []byte {
+	return []byte("st/" + owner + "/" + name)
+}
+
+func (c *ClientWithStaleData) serializeProjects(entry projectsDBEntry) ([]byte, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling json: %w", err)
+	}
+
+	return data, nil
+}
+
+func (c *ClientWithStaleData) unserializeProjects(data []byte) (*projectsDBEntry, error) {
+	var entry projectsDBEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshalling json: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (c *ClientWithStaleData) serializeStats(entry statsDBEntry) ([]byte, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling json: %w", err)
+	}
+
+	return data, nil
+}
+
+func (c *ClientWithStaleData) unserializeStats(data []byte) (*statsDBEntry, error) {
+	var entry statsDBEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshalling json: %w", err)
+	}
+
+	return &entry, nil
+}
+
+type projectsDBEntry struct {
+	Created  int64
+	Count    int
+	Data     []app.Project
+	NotFound bool `json:",omitempty"`
+}
+type statsDBEntry struct {
+	Created int64
+	Data    []app.ContributorStats
+	Validators
+	NotFound bool `json:",omitempty"`
+}
+
+type rateLimit struct {
+	until     time.Time
+	exhausted bool
+}
+
+type projectsDBUpdateRequest struct {
+	query app.ProjectsQuery
+	count int
+	// base is an entry to extend. If nil, all projects are retrieved.
+	base *projectsDBEntry
+}
+
+type statsDBUpdateRequest struct {
+	name  string
+	owner string
+	// current is an entry saved in db. If not nil, its data is kept when it's still up to date.
+	current *statsDBEntry
+}
@@ -0,0 +1,133 @@
+package forge
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPClientConfig holds settings of http client used for api requests.
+type HTTPClientConfig struct {
+	// Timeout is the time limit of a single request, including reading response body. 0 means no limit.
+	Timeout time.Duration
+	// DialTimeout is the time limit of establishing tcp connection.
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout is the time limit of tls handshake.
+	TLSHandshakeTimeout time.Duration
+	// IdleConnTimeout is the time after which idle connection is closed. 0 means no limit.
+	IdleConnTimeout time.Duration
+
+	// MaxIdleConns is the maximum number of idle connections to all hosts. 0 means no limit.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections to a single host.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost is the maximum number of connections to a single host. 0 means no limit.
+	MaxConnsPerHost int
+
+	// CAFile is optional path to PEM bundle of root CAs trusted in addition to the system ones.
+	CAFile string
+	// ClientCertFile and ClientKeyFile are optional paths to PEM encoded client certificate and its key.
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// ProxyURL is optional proxy address. If empty, proxy is taken from HTTP_PROXY and HTTPS_PROXY env variables.
+	ProxyURL string
+}
+
+// NewHTTPClient validates config and creates http client.
+func NewHTTPClient(conf HTTPClientConfig) (*http.Client, error) {
+	if conf.Timeout < 0 || conf.DialTimeout < 0 || conf.TLSHandshakeTimeout < 0 || conf.IdleConnTimeout < 0 {
+		return nil, errors.New("timeouts can't be negative")
+	}
+	if conf.MaxIdleConns < 0 || conf.MaxIdleConnsPerHost < 0 || conf.MaxConnsPerHost < 0 {
+		return nil, errors.New("connection pool sizes can't be negative")
+	}
+
+	tlsConfig, err := newTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if conf.ProxyURL != "" {
+		u, err := ParseHTTPURL(conf.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   conf.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: conf.TLSHandshakeTimeout,
+		IdleConnTimeout:     conf.IdleConnTimeout,
+		MaxIdleConns:        conf.MaxIdleConns,
+		MaxIdleConnsPerHost: conf.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     conf.MaxConnsPerHost,
+		ForceAttemptHTTP2:   true,
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   conf.Timeout,
+	}, nil
+}
+
+func newTLSConfig(conf HTTPClientConfig) (*tls.Config, error) {
+	var tlsConfig tls.Config
+
+	if conf.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		data, err := ioutil.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in ca file %s", conf.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (conf.ClientCertFile == "") != (conf.ClientKeyFile == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+	if conf.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.ClientCertFile, conf.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &tlsConfig, nil
+}
+
+// ParseHTTPURL parses absolute http or https url.
+func ParseHTTPURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("empty host")
+	}
+
+	return u, nil
+}
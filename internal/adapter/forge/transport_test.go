@@ -0,0 +1,201 @@
+package forge
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClientInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "transport")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	invalidPEMFile := writeTestFile(t, dir, "invalid.pem", []byte("not a certificate"))
+
+	tests := []struct {
+		name string
+		conf HTTPClientConfig
+	}{
+		{
+			name: "negative timeout",
+			conf: HTTPClientConfig{Timeout: -time.Second},
+		},
+		{
+			name: "negative dial timeout",
+			conf: HTTPClientConfig{DialTimeout: -time.Second},
+		},
+		{
+			name: "negative pool size",
+			conf: HTTPClientConfig{MaxIdleConnsPerHost: -1},
+		},
+		{
+			name: "missing ca file",
+			conf: HTTPClientConfig{CAFile: filepath.Join(dir, "missing.pem")},
+		},
+		{
+			name: "ca file without certificates",
+			conf: HTTPClientConfig{CAFile: invalidPEMFile},
+		},
+		{
+			name: "client certificate without key",
+			conf: HTTPClientConfig{ClientCertFile: invalidPEMFile},
+		},
+		{
+			name: "invalid client certificate",
+			conf: HTTPClientConfig{ClientCertFile: invalidPEMFile, ClientKeyFile: invalidPEMFile},
+		},
+		{
+			name: "proxy url without scheme",
+			conf: HTTPClientConfig{ProxyURL: "proxy.example.com:3128"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHTTPClient(tt.conf)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewHTTPClientCustomCA(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "transport")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clientCert, clientKey := newTestCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := writeTestFile(t, dir, "ca.pem", pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}))
+	certFile := writeTestFile(t, dir, "client.pem", pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: clientCert.Raw,
+	}))
+	keyBytes, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+	keyFile := writeTestFile(t, dir, "client.key", pem.EncodeToMemory(&pem.Block{
+		Type:  "EC PRIVATE KEY",
+		Bytes: keyBytes,
+	}))
+
+	tests := []struct {
+		name    string
+		conf    HTTPClientConfig
+		wantErr bool
+	}{
+		{
+			name:    "server's ca not trusted",
+			conf:    HTTPClientConfig{ClientCertFile: certFile, ClientKeyFile: keyFile},
+			wantErr: true,
+		},
+		{
+			name:    "no client certificate",
+			conf:    HTTPClientConfig{CAFile: caFile},
+			wantErr: true,
+		},
+		{
+			name: "custom ca and client certificate",
+			conf: HTTPClientConfig{CAFile: caFile, ClientCertFile: certFile, ClientKeyFile: keyFile},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.conf.Timeout = 5 * time.Second
+			client, err := NewHTTPClient(tt.conf)
+			require.NoError(t, err)
+
+			resp, err := client.Get(server.URL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	t.Parallel()
+
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Timeout:  5 * time.Second,
+		ProxyURL: proxy.URL,
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://github.example.com/api/v3/search/repositories")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "github.example.com", proxiedHost)
+}
+
+// newTestCertificate creates self-signed client certificate.
+func newTestCertificate(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "goprojectdemo"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func writeTestFile(t *testing.T, dir string, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+
+	return path
+}
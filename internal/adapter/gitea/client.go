@@ -0,0 +1,248 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// pageSize is the number of items requested in a single page. Gitea's default maximum is 50.
+	pageSize = 50
+	// projectsMaxResults is the maximum number of projects returned for a single query.
+	projectsMaxResults = 1000
+	// projectsMaxPages limits search pages retrieved for a single query, as projects are filtered client-side.
+	// Projects in less popular languages may be missing from results, as only the most starred projects are searched.
+	projectsMaxPages = 40
+)
+
+// Client returns details about projects and stats hosted on Gitea or Forgejo.
+// This struct is an adapter for app.ForgeClient.
+type Client struct {
+	doer    forge.HTTPDoer
+	address string
+	token   string
+
+	// emailIDs identifies authors of commits without linked gitea account.
+	emailIDs *forge.EmailIDs
+
+	// commitsMaxPages limits commits retrieved for project's stats, only the most recent ones are counted.
+	commitsMaxPages int
+	responseMaxSize int
+
+	l logrus.FieldLogger
+}
+
+var _ app.ForgeClient = &Client{}
+
+// NewClient creates new gitea client. address is gitea api address, e.g. "https://codeberg.org/api/v1".
+// authToken is optional, without it only public projects are available.
+// commitsMaxPages limits pages of commits retrieved for project's stats, each page holds 50 commits.
+// Truncated results are logged.
+func NewClient(
+	doer forge.HTTPDoer,
+	address string,
+	authToken string,
+	commitsMaxPages int,
+	l logrus.FieldLogger,
+) (*Client, error) {
+	if commitsMaxPages < 1 {
+		return nil, errors.New("commits max pages must be positive")
+	}
+
+	return &Client{
+		doer:    doer,
+		address: strings.TrimSuffix(address, "/"),
+		token:   authToken,
+
+		emailIDs: forge.NewEmailIDs(),
+
+		commitsMaxPages: commitsMaxPages,
+		responseMaxSize: 1024 * 1024 * 10,
+
+		l: l,
+	}, nil
+}
+
+// ProjectsByLanguage returns projects by given programming language name and filters, ordered by the number of stars.
+//
+// Gitea's search api doesn't filter by language, so projects are searched by stars and filtered by their
+// primary language. Other filters, except for archived projects, are applied to retrieved pages too.
+// Search stops after projectsMaxPages pages, so fewer than count projects may be returned.
+// Only a single topic is supported, license filter is not supported.
+// See: https://try.gitea.io/api/swagger#/repository/repoSearch
+func (c *Client) ProjectsByLanguage(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error) {
+	if q.Language == "" {
+		return nil, app.InvalidRequestError("lanuage cannot be empty")
+	}
+	if count < 1 || count > projectsMaxResults {
+		return nil, app.InvalidRequestError(fmt.Sprintf("count must be in range <1..%d>", projectsMaxResults))
+	}
+	if q.Filter.License != "" {
+		return nil, app.InvalidRequestError("license filter is not supported by gitea")
+	}
+	if len(q.Filter.Topics) > 1 {
+		return nil, app.InvalidRequestError("gitea supports a single topic filter")
+	}
+
+	u, err := c.searchURL(q)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]app.Project, 0, count)
+	pages := 0
+	for ; u != "" && len(projects) < count && pages < projectsMaxPages; pages++ {
+		var page searchResponse
+		page, u, err = c.searchPage(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range page.Data {
+			// Projects are ordered by stars, none of the next ones has enough of them.
+			if r.Stars < q.Filter.MinStars {
+				u = ""
+				break
+			}
+			if r.matches(q) {
+				projects = append(projects, r.ToProject())
+			}
+		}
+	}
+	if u != "" && len(projects) < count {
+		c.l.Warnf(
+			"gitea client: search for %s projects truncated after %d pages, found %d of %d projects",
+			q.Language, pages, len(projects), count,
+		)
+	}
+	if len(projects) > count {
+		projects = projects[:count]
+	}
+
+	return projects, nil
+}
+
+func (c *Client) searchURL(q app.ProjectsQuery) (string, error) {
+	u, err := url.Parse(c.address + "/repos/search")
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+
+	v := make(url.Values)
+	v.Set("sort", "stars")
+	v.Set("order", "desc")
+	v.Set("limit", strconv.Itoa(pageSize))
+	if q.Filter.ExcludeArchived {
+		v.Set("archived", "false")
+	}
+	if len(q.Filter.Topics) == 1 {
+		v.Set("q", q.Filter.Topics[0])
+		v.Set("topic", "true")
+	}
+	u.RawQuery = v.Encode()
+
+	return u.String(), nil
+}
+
+// searchPage returns search results page under given url, and url of the next page if there is one.
+func (c *Client) searchPage(ctx context.Context, u string) (searchResponse, string, error) {
+	resp, err := c.get(ctx, u)
+	if err != nil {
+		return searchResponse{}, "", fmt.Errorf("making http request: %w", err)
+	}
+
+	var page searchResponse
+	if err := json.Unmarshal(resp.Body, &page); err != nil {
+		return searchResponse{}, "", fmt.Errorf("unmarshalling response: %w", err)
+	}
+
+	return page, forge.NextPageURL(resp.Header), nil
+}
+
+// StatsByProject returns stats of project's contributors, computed from project's commits.
+//
+// Only commit counts with weekly activity are available, so the service rejects rankings by lines for gitea.
+// Commits of authors without gitea account are attributed to author's name, with id assigned to the email,
+// see forge.EmailIDs. Only the most recent commits are counted for projects with very long history.
+// See: https://try.gitea.io/api/swagger#/repository/repoGetAllCommits
+func (c *Client) StatsByProject(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+	if name == "" {
+		return nil, app.InvalidRequestError("project's name cannot be empty")
+	}
+	if owner == "" {
+		return nil, app.InvalidRequestError("project's owner login cannot be empty")
+	}
+
+	u, err := url.Parse(fmt.Sprintf("%s/repos/%s/%s/commits", c.address, url.PathEscape(owner), url.PathEscape(name)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	v := make(url.Values)
+	v.Set("limit", strconv.Itoa(pageSize))
+	// Diff stats and files are expensive to compute and not used.
+	v.Set("stat", "false")
+	v.Set("verification", "false")
+	v.Set("files", "false")
+	u.RawQuery = v.Encode()
+
+	var commits commitsResponse
+	next := u.String()
+	for pages := 0; next != "" && pages < c.commitsMaxPages; pages++ {
+		resp, err := c.get(ctx, next)
+		if err != nil {
+			return nil, fmt.Errorf("making http request: %w", err)
+		}
+		if resp.Status == http.StatusConflict {
+			return commits.ToStats(c.emailIDs), nil
+		}
+
+		var page commitsResponse
+		if err := json.Unmarshal(resp.Body, &page); err != nil {
+			return nil, fmt.Errorf("unmarshalling response: %w", err)
+		}
+		commits = append(commits, page...)
+		next = forge.NextPageURL(resp.Header)
+	}
+	if next != "" {
+		c.l.Warnf(
+			"gitea client: stats of %s/%s truncated to %d most recent commits",
+			owner, name, len(commits),
+		)
+	}
+
+	return commits.ToStats(c.emailIDs), nil
+}
+
+func (c *Client) get(ctx context.Context, u string) (*forge.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+
+	resp, err := forge.Do(ctx, c.doer, req, c.responseMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	// Gitea responds with 409 for empty repositories.
+	if resp.Status == http.StatusConflict {
+		return resp, nil
+	}
+	if err := forge.StatusError(req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,502 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitea serves repositories and commits of a fake gitea instance, two items per page.
+type fakeGitea struct {
+	repos   []string
+	commits []string
+
+	m        sync.Mutex
+	requests []*http.Request
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.m.Lock()
+	f.requests = append(f.requests, r)
+	f.m.Unlock()
+
+	var items []string
+	switch r.URL.Path {
+	case "/api/v1/repos/search":
+		items = f.repos
+	case "/api/v1/repos/owner/project/commits":
+		items = f.commits
+	case "/api/v1/repos/owner/empty/commits":
+		w.WriteHeader(http.StatusConflict)
+		return
+	case "/api/v1/repos/owner/limited/commits":
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	case "/api/v1/repos/owner/broken/commits":
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	page := 1
+	fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+	start, end := (page-1)*2, page*2
+	if end >= len(items) {
+		end = len(items)
+	} else {
+		q := r.URL.Query()
+		q.Set("page", fmt.Sprint(page+1))
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?%s>; rel="next"`, r.Host, r.URL.Path, q.Encode()))
+	}
+	if start > end {
+		start = end
+	}
+
+	var body string
+	for i, item := range items[start:end] {
+		if i > 0 {
+			body += ","
+		}
+		body += item
+	}
+	if r.URL.Path == "/api/v1/repos/search" {
+		fmt.Fprintf(w, `{"ok":true,"data":[%s]}`, body)
+		return
+	}
+	fmt.Fprintf(w, "[%s]", body)
+}
+
+func giteaRepo(id int, stars int, language string, fork bool) string {
+	return fmt.Sprintf(
+		`{"id":%d,"name":"project%d","owner":{"login":"owner"},"stars_count":%d,"forks_count":1,`+
+			`"description":"Project %d","html_url":"https://codeberg.org/owner/project%d","default_branch":"main",`+
+			`"updated_at":"2020-05-01T10:00:00Z","language":"%s","fork":%t}`,
+		id, id, stars, id, id, language, fork,
+	)
+}
+
+func giteaCommit(name string, email string, date string, accountID int) string {
+	author := "null"
+	if accountID > 0 {
+		author = fmt.Sprintf(`{"id":%d,"login":"%s"}`, accountID, name)
+	}
+	return fmt.Sprintf(
+		`{"sha":"abc","commit":{"author":{"name":"%s","email":"%s","date":"%s"}},"author":%s}`,
+		name, email, date, author,
+	)
+}
+
+// newTestClient creates client of gitea api under given address. Returned hook holds client's log entries.
+func newTestClient(t *testing.T, address string, token string, commitsMaxPages int) (*Client, *test.Hook) {
+	l, hook := test.NewNullLogger()
+	c, err := NewClient(http.DefaultClient, address, token, commitsMaxPages, l)
+	require.NoError(t, err)
+
+	return c, hook
+}
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClient(http.DefaultClient, "https://fake/api/v1", "", 0, logrus.New())
+	assert.Error(t, err)
+}
+
+func TestClient_ProjectsByLanguage(t *testing.T) {
+	t.Parallel()
+
+	repos := []string{
+		giteaRepo(1, 50, "Go", false),
+		giteaRepo(2, 40, "Rust", false),
+		giteaRepo(3, 30, "Go", true),
+		giteaRepo(4, 20, "Go", false),
+		giteaRepo(5, 10, "Go", false),
+	}
+	// Only the first of many pages holds a project in searched language.
+	var manyRepos []string
+	for i := 1; i <= 2*projectsMaxPages+2; i++ {
+		language := "Rust"
+		if i == 1 {
+			language = "Go"
+		}
+		manyRepos = append(manyRepos, giteaRepo(i, 1000-i, language, false))
+	}
+
+	tests := []struct {
+		name       string
+		repos      []string
+		query      app.ProjectsQuery
+		count      int
+		wantIDs    []int
+		wantParams url.Values
+		wantPages  int
+		wantWarn   bool
+		wantErr    bool
+	}{
+		{
+			name:    "empty language",
+			query:   app.ProjectsQuery{},
+			count:   1,
+			wantErr: true,
+		},
+		{
+			name:    "invalid count",
+			query:   app.ProjectsQuery{Language: "go"},
+			count:   1001,
+			wantErr: true,
+		},
+		{
+			name: "license filter",
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter:   app.ProjectFilter{License: "mit"},
+			},
+			count:   1,
+			wantErr: true,
+		},
+		{
+			name: "many topics",
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter:   app.ProjectFilter{Topics: []string{"cli", "tools"}},
+			},
+			count:   1,
+			wantErr: true,
+		},
+		{
+			name:    "language filtered",
+			repos:   repos,
+			query:   app.ProjectsQuery{Language: "go"},
+			count:   2,
+			wantIDs: []int{1, 3},
+			wantParams: url.Values{
+				"sort":  {"stars"},
+				"order": {"desc"},
+				"limit": {"50"},
+			},
+			wantPages: 2,
+		},
+		{
+			name:      "all pages",
+			repos:     repos,
+			query:     app.ProjectsQuery{Language: "go"},
+			count:     10,
+			wantIDs:   []int{1, 3, 4, 5},
+			wantPages: 3,
+		},
+		{
+			name:  "server side filters",
+			repos: repos,
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter: app.ProjectFilter{
+					ExcludeArchived: true,
+					Topics:          []string{"cli"},
+				},
+			},
+			count:   1,
+			wantIDs: []int{1},
+			wantParams: url.Values{
+				"sort":     {"stars"},
+				"order":    {"desc"},
+				"limit":    {"50"},
+				"archived": {"false"},
+				"q":        {"cli"},
+				"topic":    {"true"},
+			},
+			wantPages: 1,
+		},
+		{
+			name:  "forks excluded",
+			repos: repos,
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter:   app.ProjectFilter{ExcludeForks: true},
+			},
+			count:     10,
+			wantIDs:   []int{1, 4, 5},
+			wantPages: 3,
+		},
+		{
+			name:  "min stars stops paging",
+			repos: repos,
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter:   app.ProjectFilter{MinStars: 30},
+			},
+			count:     10,
+			wantIDs:   []int{1, 3},
+			wantPages: 2,
+		},
+		{
+			name:  "pushed after",
+			repos: repos,
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter:   app.ProjectFilter{PushedAfter: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			count:     10,
+			wantPages: 3,
+		},
+		{
+			name:      "search truncated",
+			repos:     manyRepos,
+			query:     app.ProjectsQuery{Language: "go"},
+			count:     10,
+			wantIDs:   []int{1},
+			wantPages: projectsMaxPages,
+			wantWarn:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeGitea{repos: tt.repos}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			c, hook := newTestClient(t, server.URL+"/api/v1/", "token", 100)
+
+			got, err := c.ProjectsByLanguage(context.Background(), tt.query, tt.count)
+			if tt.wantErr {
+				assert.True(t, app.IsInvalidRequestError(err))
+				assert.Empty(t, fake.requests)
+				return
+			}
+			require.NoError(t, err)
+
+			var ids []int
+			for _, p := range got {
+				ids = append(ids, p.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+
+			require.Len(t, fake.requests, tt.wantPages)
+			for _, r := range fake.requests {
+				assert.Equal(t, "token token", r.Header.Get("Authorization"))
+				assert.Equal(t, "/api/v1/repos/search", r.URL.Path)
+			}
+			if tt.wantParams != nil {
+				assert.Equal(t, tt.wantParams, fake.requests[0].URL.Query())
+			}
+
+			if tt.wantWarn {
+				require.Len(t, hook.AllEntries(), 1)
+				assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+			} else {
+				assert.Empty(t, hook.AllEntries())
+			}
+		})
+	}
+}
+
+func TestClient_ProjectsByLanguageMapping(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(&fakeGitea{
+		repos: []string{giteaRepo(1, 50, "Go", false)},
+	})
+	defer server.Close()
+	c, _ := newTestClient(t, server.URL+"/api/v1", "", 100)
+
+	projects, err := c.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []app.Project{
+		{
+			ID:            1,
+			Name:          "project1",
+			OwnerLogin:    "owner",
+			Stars:         50,
+			Forks:         1,
+			Description:   "Project 1",
+			URL:           "https://codeberg.org/owner/project1",
+			DefaultBranch: "main",
+			PushedAt:      time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC),
+		},
+	}, projects)
+}
+
+func TestClient_StatsByProject(t *testing.T) {
+	t.Parallel()
+
+	// Commits of both authors are spread over three pages, John's email differs in case between pages.
+	commits := []string{
+		giteaCommit("jane", "jane@example.com", "2020-05-07T10:00:00Z", 7),
+		giteaCommit("John Doe", "john@example.com", "2020-05-06T10:00:00Z", 0),
+		giteaCommit("jane", "jane@example.com", "2020-05-03T00:00:00Z", 7),
+		giteaCommit("jane", "jane@example.com", "2020-05-02T23:59:59Z", 7),
+		giteaCommit("John Doe", "JOHN@example.com", "2020-04-01T10:00:00Z", 0),
+	}
+
+	tests := []struct {
+		name            string
+		project         string
+		owner           string
+		commitsMaxPages int
+		want            []app.ContributorStats
+		wantPages       int
+		wantWarn        bool
+		wantInvalid     bool
+		wantNotFound    bool
+		wantRetryAfter  time.Duration
+		wantErr         bool
+	}{
+		{
+			name:        "empty name",
+			owner:       "owner",
+			wantInvalid: true,
+			wantErr:     true,
+		},
+		{
+			name:        "empty owner",
+			project:     "project",
+			wantInvalid: true,
+			wantErr:     true,
+		},
+		{
+			name:    "authors aggregated across pages",
+			project: "project",
+			owner:   "owner",
+			want: []app.ContributorStats{
+				{
+					Contributor: app.Contributor{
+						ID:    7,
+						Login: "jane",
+					},
+					Commits: 3,
+					Weeks: []app.WeeklyStats{
+						{Start: time.Date(2020, 4, 26, 0, 0, 0, 0, time.UTC), Commits: 1},
+						{Start: time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC), Commits: 2},
+					},
+				},
+				{
+					Contributor: app.Contributor{
+						ID:    forge.NewEmailIDs().ID("john@example.com"),
+						Login: "John Doe",
+					},
+					Commits: 2,
+					Weeks: []app.WeeklyStats{
+						{Start: time.Date(2020, 3, 29, 0, 0, 0, 0, time.UTC), Commits: 1},
+						{Start: time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC), Commits: 1},
+					},
+				},
+			},
+			wantPages: 3,
+		},
+		{
+			name:            "commits truncated",
+			project:         "project",
+			owner:           "owner",
+			commitsMaxPages: 1,
+			want: []app.ContributorStats{
+				{
+					Contributor: app.Contributor{
+						ID:    7,
+						Login: "jane",
+					},
+					Commits: 1,
+					Weeks: []app.WeeklyStats{
+						{Start: time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC), Commits: 1},
+					},
+				},
+				{
+					Contributor: app.Contributor{
+						ID:    forge.NewEmailIDs().ID("john@example.com"),
+						Login: "John Doe",
+					},
+					Commits: 1,
+					Weeks: []app.WeeklyStats{
+						{Start: time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC), Commits: 1},
+					},
+				},
+			},
+			wantPages: 1,
+			wantWarn:  true,
+		},
+		{
+			name:      "empty repository",
+			project:   "empty",
+			owner:     "owner",
+			want:      []app.ContributorStats{},
+			wantPages: 1,
+		},
+		{
+			name:         "not found",
+			project:      "missing",
+			owner:        "owner",
+			wantPages:    1,
+			wantNotFound: true,
+			wantErr:      true,
+		},
+		{
+			name:           "rate limited",
+			project:        "limited",
+			owner:          "owner",
+			wantPages:      1,
+			wantRetryAfter: 30 * time.Second,
+			wantErr:        true,
+		},
+		{
+			name:      "server error",
+			project:   "broken",
+			owner:     "owner",
+			wantPages: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeGitea{commits: commits}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			commitsMaxPages := tt.commitsMaxPages
+			if commitsMaxPages == 0 {
+				commitsMaxPages = 100
+			}
+			c, hook := newTestClient(t, server.URL+"/api/v1", "", commitsMaxPages)
+
+			got, err := c.StatsByProject(context.Background(), tt.project, tt.owner)
+			require.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantInvalid, app.IsInvalidRequestError(err))
+			assert.Equal(t, tt.wantNotFound, app.IsNotFoundError(err))
+
+			var rateLimitErr app.RateLimitError
+			assert.Equal(t, tt.wantRetryAfter != 0, errors.As(err, &rateLimitErr))
+			assert.Equal(t, tt.wantRetryAfter, rateLimitErr.RetryAfter)
+
+			require.Len(t, fake.requests, tt.wantPages)
+			for _, r := range fake.requests {
+				assert.Empty(t, r.Header.Get("Authorization"))
+				assert.Equal(t, "false", r.URL.Query().Get("stat"))
+			}
+
+			if tt.wantWarn {
+				require.Len(t, hook.AllEntries(), 1)
+				assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+			} else {
+				assert.Empty(t, hook.AllEntries())
+			}
+		})
+	}
+}
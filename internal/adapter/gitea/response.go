@@ -0,0 +1,135 @@
+package gitea
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+type searchResponse struct {
+	Data []searchResponseRepo `json:"data"`
+}
+
+type searchResponseRepo struct {
+	ID            int                     `json:"id"`
+	Name          string                  `json:"name"`
+	Owner         searchResponseRepoOwner `json:"owner"`
+	Stars         int                     `json:"stars_count"`
+	Forks         int                     `json:"forks_count"`
+	Description   string                  `json:"description"`
+	URL           string                  `json:"html_url"`
+	DefaultBranch string                  `json:"default_branch"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+	Language      string                  `json:"language"`
+	Fork          bool                    `json:"fork"`
+	Archived      bool                    `json:"archived"`
+}
+
+type searchResponseRepoOwner struct {
+	Login string `json:"login"`
+}
+
+// matches tells if repository matches query's language and filters not supported by gitea's search.
+func (r searchResponseRepo) matches(q app.ProjectsQuery) bool {
+	if !strings.EqualFold(r.Language, q.Language) {
+		return false
+	}
+	if q.Filter.ExcludeForks && r.Fork {
+		return false
+	}
+	if !q.Filter.PushedAfter.IsZero() && !r.UpdatedAt.After(q.Filter.PushedAfter) {
+		return false
+	}
+
+	return true
+}
+
+func (r searchResponseRepo) ToProject() app.Project {
+	return app.Project{
+		ID:            r.ID,
+		Name:          r.Name,
+		OwnerLogin:    r.Owner.Login,
+		Stars:         r.Stars,
+		Forks:         r.Forks,
+		Description:   r.Description,
+		URL:           r.URL,
+		DefaultBranch: r.DefaultBranch,
+		PushedAt:      r.UpdatedAt.UTC(),
+	}
+}
+
+type commitsResponse []struct {
+	Commit commitsResponseCommit `json:"commit"`
+	// Author is the gitea account of commit's author, nil if author's email isn't linked to any account.
+	Author *commitsResponseUser `json:"author"`
+}
+
+type commitsResponseCommit struct {
+	Author commitsResponseSignature `json:"author"`
+}
+
+type commitsResponseSignature struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+type commitsResponseUser struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+}
+
+// ToStats counts commits of each author, with weekly activity ordered by week start.
+func (s commitsResponse) ToStats(ids *forge.EmailIDs) []app.ContributorStats {
+	type authorStats struct {
+		stats app.ContributorStats
+		weeks map[time.Time]int
+	}
+
+	var order []int
+	authors := make(map[int]*authorStats)
+	for _, el := range s {
+		contributor := app.Contributor{
+			ID:    ids.ID(el.Commit.Author.Email),
+			Login: el.Commit.Author.Name,
+		}
+		if el.Author != nil && el.Author.ID > 0 {
+			contributor = app.Contributor{
+				ID:    el.Author.ID,
+				Login: el.Author.Login,
+			}
+		}
+
+		a, ok := authors[contributor.ID]
+		if !ok {
+			a = &authorStats{
+				stats: app.ContributorStats{Contributor: contributor},
+				weeks: make(map[time.Time]int),
+			}
+			authors[contributor.ID] = a
+			order = append(order, contributor.ID)
+		}
+		a.stats.Commits++
+		a.weeks[forge.WeekStart(el.Commit.Author.Date)]++
+	}
+
+	ss := make([]app.ContributorStats, 0, len(order))
+	for _, id := range order {
+		a := authors[id]
+		for start, commits := range a.weeks {
+			a.stats.Weeks = append(a.stats.Weeks, app.WeeklyStats{
+				Start:   start,
+				Commits: commits,
+			})
+		}
+		sort.Slice(a.stats.Weeks, func(i, j int) bool {
+			return a.stats.Weeks[i].Start.Before(a.stats.Weeks[j].Start)
+		})
+		ss = append(ss, a.stats)
+	}
+
+	return ss
+}
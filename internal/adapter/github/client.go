@@ -3,182 +3,300 @@ package github
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
 	"github.com/m-zajac/goprojectdemo/internal/app"
 )
 
-// HTTPDoer can execute http request.
-type HTTPDoer interface {
-	Do(*http.Request) (*http.Response, error)
-}
+// searchMaxResults is the maximum number of search results github provides.
+const searchMaxResults = forge.ProjectsMaxResults
 
 // Client returns details about gihub projects and stats.
-// This struct is an adapter for app.GithubClient.
-//go:generate mockgen -destination mock/githubcli.go -package mock github.com/m-zajac/goprojectdemo/internal/app GithubClient
+// This struct is an adapter for app.ForgeClient.
 type Client struct {
-	doer           HTTPDoer
-	address        string
-	authToken      string
-	acceptWaitTime time.Duration
+	doer        forge.HTTPDoer
+	address     string
+	credentials CredentialProvider
 
 	projectsResponseMaxSize int
 	statsResponseMaxSize    int
-	numRetriesOnAccepted    int
 }
 
-var _ app.GithubClient = &Client{}
+var _ app.ForgeClient = &Client{}
+var _ forge.ProjectsPager = &Client{}
 
 // NewClient creates new github client.
 // authToken is optional.
-func NewClient(doer HTTPDoer, address string, authToken string) *Client {
+func NewClient(doer forge.HTTPDoer, address string, authToken string) *Client {
+	return NewClientWithCredentials(doer, address, StaticToken(authToken))
+}
+
+// NewClientWithCredentials creates new github client authorizing requests with tokens from given provider.
+func NewClientWithCredentials(doer forge.HTTPDoer, address string, credentials CredentialProvider) *Client {
 	c := Client{
-		doer:           doer,
-		address:        address,
-		authToken:      authToken,
-		acceptWaitTime: 5 * time.Second,
+		doer:        doer,
+		address:     address,
+		credentials: credentials,
 
 		projectsResponseMaxSize: 1024 * 1024 * 10,
 		statsResponseMaxSize:    1024 * 1024 * 30,
-		numRetriesOnAccepted:    7,
 	}
 
 	return &c
 }
 
-// ProjectsByLanguage returns projects by given programming language name.
-func (c *Client) ProjectsByLanguage(ctx context.Context, language string, count int) ([]app.Project, error) {
-	if language == "" {
+// ProjectsByLanguage returns projects by given programming language name and filters.
+// Up to 1000 projects can be returned, more than 100 projects are retrieved page by page.
+func (c *Client) ProjectsByLanguage(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error) {
+	if q.Language == "" {
 		return nil, app.InvalidRequestError("lanuage cannot be empty")
 	}
-	if count < 1 || count > 99 {
-		return nil, app.InvalidRequestError("count must be in range <1..99>")
+	if count < 1 || count > searchMaxResults {
+		return nil, app.InvalidRequestError(fmt.Sprintf("count must be in range <1..%d>", searchMaxResults))
+	}
+
+	perPage := count
+	if perPage > forge.ProjectsPageSize {
+		perPage = forge.ProjectsPageSize
+	}
+	u, err := c.searchURL(q, 1, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]app.Project, 0, count)
+	for u != "" && len(projects) < count {
+		var page []app.Project
+		page, u, err = c.searchPage(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, page...)
+	}
+	if len(projects) > count {
+		projects = projects[:count]
+	}
+
+	return projects, nil
+}
+
+// ProjectsPage returns given page of projects by programming language name and filters.
+// Pages are numbered from 1 and hold forge.ProjectsPageSize projects. hasNext tells if there are more pages.
+func (c *Client) ProjectsPage(ctx context.Context, q app.ProjectsQuery, page int) ([]app.Project, bool, error) {
+	if q.Language == "" {
+		return nil, false, app.InvalidRequestError("lanuage cannot be empty")
+	}
+	if maxPage := searchMaxResults / forge.ProjectsPageSize; page < 1 || page > maxPage {
+		return nil, false, app.InvalidRequestError(fmt.Sprintf("page must be in range <1..%d>", maxPage))
+	}
+
+	u, err := c.searchURL(q, page, forge.ProjectsPageSize)
+	if err != nil {
+		return nil, false, err
+	}
+	projects, next, err := c.searchPage(ctx, u)
+	if err != nil {
+		return nil, false, err
 	}
 
+	return projects, next != "", nil
+}
+
+func (c *Client) searchURL(q app.ProjectsQuery, page int, perPage int) (string, error) {
 	u, err := url.Parse(c.address + "/search/repositories")
 	if err != nil {
-		return nil, fmt.Errorf("invalid url: %w", err)
+		return "", fmt.Errorf("invalid url: %w", err)
 	}
 
 	v := make(url.Values)
-	v.Set("q", "language:"+language)
+	v.Set("q", searchQuery(q))
 	v.Set("sort", "stars")
-	v.Set("per_page", strconv.Itoa(count))
+	v.Set("per_page", strconv.Itoa(perPage))
+	if page > 1 {
+		v.Set("page", strconv.Itoa(page))
+	}
 	u.RawQuery = v.Encode()
 
-	httpReq, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	return u.String(), nil
+}
+
+// searchPage returns projects from search results page under given url, and url of the next page if there is one.
+func (c *Client) searchPage(ctx context.Context, u string) ([]app.Project, string, error) {
+	httpReq, err := http.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating http request: %w", err)
+		return nil, "", fmt.Errorf("creating http request: %w", err)
 	}
 
-	body, _, err := c.makeRequest(ctx, httpReq, c.projectsResponseMaxSize)
+	resp, err := c.makeRequest(ctx, httpReq, c.projectsResponseMaxSize)
 	if err != nil {
-		return nil, fmt.Errorf("making http request: %w", err)
+		return nil, "", fmt.Errorf("making http request: %w", err)
 	}
 
-	var resp searchResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("unmarshalling response: %w", err)
+	var page searchResponse
+	if err := json.Unmarshal(resp.Body, &page); err != nil {
+		return nil, "", fmt.Errorf("unmarshalling response: %w", err)
 	}
 
-	return resp.ToProjects(), nil
+	return page.ToProjects(), forge.NextPageURL(resp.Header), nil
 }
 
 // StatsByProject returns stats by given github project params.
 func (c *Client) StatsByProject(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+	stats, _, _, err := c.StatsByProjectIfModified(ctx, name, owner, forge.Validators{})
+	return stats, err
+}
+
+// StatsByProjectIfModified returns stats by given github project params, if they changed since version identified by v.
+// Responses with status 304 don't count against api rate limit.
+func (c *Client) StatsByProjectIfModified(
+	ctx context.Context,
+	name string,
+	owner string,
+	v forge.Validators,
+) ([]app.ContributorStats, forge.Validators, bool, error) {
 	if name == "" {
-		return nil, app.InvalidRequestError("project's name cannot be empty")
+		return nil, forge.Validators{}, false, app.InvalidRequestError("project's name cannot be empty")
 	}
 	if owner == "" {
-		return nil, app.InvalidRequestError("project's owner login cannot be empty")
+		return nil, forge.Validators{}, false, app.InvalidRequestError("project's owner login cannot be empty")
 	}
 
 	u, err := url.Parse(c.address + fmt.Sprintf("/repos/%s/%s/stats/contributors", owner, name))
 	if err != nil {
-		return nil, fmt.Errorf("invalid url: %w", err)
+		return nil, forge.Validators{}, false, fmt.Errorf("invalid url: %w", err)
 	}
 
 	httpReq, err := http.NewRequest(http.MethodGet, u.String(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating http request: %w", err)
+		return nil, forge.Validators{}, false, fmt.Errorf("creating http request: %w", err)
 	}
+	setConditionalHeaders(httpReq, v)
 
-	// Github returns status 202 when processing data.
-	// Should wait a bit and try again.
-	var tries int
-	var body []byte
-	for {
-		tries++
-		b, code, err := c.makeRequest(ctx, httpReq, 1024*1024*100)
-		if err != nil {
-			return nil, fmt.Errorf("making http request: %w", err)
-		}
-		if code == http.StatusAccepted {
-			if tries < c.numRetriesOnAccepted {
-				time.Sleep(c.acceptWaitTime)
-				continue
-			}
-			return nil, errors.New("too many reties with status 202")
-		}
-		body = b
-		break
+	resp, err := c.makeRequest(ctx, httpReq, 1024*1024*100)
+	if err != nil {
+		return nil, forge.Validators{}, false, fmt.Errorf("making http request: %w", err)
+	}
+	// Github returns status 202 when stats are being computed. They should be requested again later.
+	if resp.Status == http.StatusAccepted {
+		return nil, forge.Validators{}, false, app.UpstreamComputingError(fmt.Sprintf("stats of %s/%s are being computed", owner, name))
+	}
+	if resp.Status == http.StatusNotModified {
+		return nil, v, false, nil
 	}
 
-	var resp statsResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("unmarshalling response: %w", err)
+	var stats statsResponse
+	if err := json.Unmarshal(resp.Body, &stats); err != nil {
+		return nil, forge.Validators{}, false, fmt.Errorf("unmarshalling response: %w", err)
 	}
 
-	return resp.ToStats(), nil
+	return stats.ToStats(), validatorsFromHeader(resp.Header), true, nil
 }
 
-func (c *Client) makeRequest(ctx context.Context, req *http.Request, maxBytes int) ([]byte, int, error) {
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "token "+c.authToken)
+// searchQuery returns github search query for given projects query.
+// See: https://docs.github.com/en/search-github/searching-on-github/searching-for-repositories
+func searchQuery(q app.ProjectsQuery) string {
+	qualifiers := []string{"language:" + q.Language}
+
+	f := q.Filter
+	if f.MinStars > 0 {
+		qualifiers = append(qualifiers, fmt.Sprintf("stars:>=%d", f.MinStars))
 	}
+	if f.ExcludeForks {
+		qualifiers = append(qualifiers, "fork:false")
+	}
+	if f.ExcludeArchived {
+		qualifiers = append(qualifiers, "archived:false")
+	}
+	for _, t := range f.Topics {
+		qualifiers = append(qualifiers, "topic:"+t)
+	}
+	if !f.PushedAfter.IsZero() {
+		qualifiers = append(qualifiers, "pushed:>"+f.PushedAfter.UTC().Format("2006-01-02"))
+	}
+	if f.License != "" {
+		qualifiers = append(qualifiers, "license:"+f.License)
+	}
+
+	return strings.Join(qualifiers, " ")
+}
 
-	resp, err := c.doer.Do(req.WithContext(ctx))
+func (c *Client) makeRequest(ctx context.Context, req *http.Request, maxBytes int) (*forge.Response, error) {
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	token, err := c.credentials.Token(ctx)
 	if err != nil {
-		return nil, 0, fmt.Errorf("doing http request: %w", err)
+		return nil, fmt.Errorf("getting auth token: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
 	}
-	// Always drain body before close to allow connection reuse.
-	// See: http://tleyden.github.io/blog/2016/11/21/tuning-the-go-http-client-library-for-load-testing/
-	defer func() {
-		_, _ = io.CopyN(ioutil.Discard, resp.Body, 1024)
-		resp.Body.Close()
-	}()
 
-	if resp.StatusCode == http.StatusNoContent {
-		return nil, resp.StatusCode, nil
+	resp, err := forge.Do(ctx, c.doer, req, maxBytes)
+	if err != nil {
+		return nil, err
 	}
-	if resp.StatusCode/100 > 3 {
-		if c.checkRateLimitExceeded(&resp.Header) {
-			return nil, resp.StatusCode, errors.New("rate limit exceeded")
+	if resp.Status/100 > 3 {
+		if err := rateLimitError(resp.Header); err != nil {
+			return nil, err
 		}
-		return nil, resp.StatusCode, fmt.Errorf("got invalid http status code: %d", resp.StatusCode)
+		if resp.Status == http.StatusUnprocessableEntity {
+			return nil, validationError(req.URL.Path, resp.Body)
+		}
+		return nil, forge.StatusError(req, resp)
 	}
 
-	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)))
-	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("reading http response body: %w", err)
+	return resp, nil
+}
+
+// validationError returns error for response with status 422.
+// Github responds with 422 to searches with unknown language or repository, which is app.NotFoundError,
+// and to searches with malformed qualifiers, which is app.InvalidRequestError.
+func validationError(path string, body []byte) error {
+	var resp validationResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("unmarshalling validation response: %w", err)
+	}
+
+	msgs := make([]string, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		if e.notFound() {
+			return app.NotFoundError(fmt.Sprintf("resource %s not found: %s", path, e.Message))
+		}
+		msgs = append(msgs, e.Message)
+	}
+	if len(msgs) == 0 {
+		msgs = append(msgs, resp.Message)
 	}
 
-	return b, resp.StatusCode, nil
+	return app.InvalidRequestError(fmt.Sprintf("request rejected by github: %s", strings.Join(msgs, "; ")))
 }
 
-func (c *Client) checkRateLimitExceeded(h *http.Header) bool {
-	if s := h.Get("X-RateLimit-Remaining"); s != "" {
-		if limit, err := strconv.Atoi(s); err == nil && limit == 0 {
-			return true
+// rateLimitError returns app.RateLimitError if response headers report exhausted rate limit, or secondary rate limit.
+// Otherwise returns nil.
+func rateLimitError(h http.Header) error {
+	if remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining")); err == nil && remaining == 0 {
+		var retryAfter time.Duration
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			retryAfter = time.Until(time.Unix(reset, 0))
+		}
+		if retryAfter < 0 {
+			retryAfter = 0
+		}
+		return app.RateLimitError{
+			RetryAfter: retryAfter,
+			Exhausted:  true,
+		}
+	}
+	if seconds, err := strconv.Atoi(h.Get("Retry-After")); err == nil && seconds >= 0 {
+		return app.RateLimitError{
+			RetryAfter: time.Duration(seconds) * time.Second,
 		}
 	}
-	return false
+
+	return nil
 }
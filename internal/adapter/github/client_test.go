@@ -2,11 +2,17 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
 	"github.com/m-zajac/goprojectdemo/internal/app"
 	"github.com/m-zajac/goprojectdemo/internal/mock"
 	"github.com/stretchr/testify/assert"
@@ -25,8 +31,10 @@ func TestClient_ProjectsByLanguage(t *testing.T) {
 		name     string
 		doer     *mock.HTTPDoer
 		language string
+		filter   app.ProjectFilter
 		count    int
 		want     []app.Project
+		wantQ    string
 		wantErr  bool
 	}{
 		{
@@ -46,7 +54,7 @@ func TestClient_ProjectsByLanguage(t *testing.T) {
 		{
 			name:     "invalid count",
 			language: "go",
-			count:    111,
+			count:    1001,
 			want:     nil,
 			wantErr:  true,
 		},
@@ -68,6 +76,16 @@ func TestClient_ProjectsByLanguage(t *testing.T) {
 									"login": "golang",
 									"id": 4314092
 								},
+								"html_url": "https://github.com/golang/go",
+								"description": "The Go programming language",
+								"pushed_at": "2020-05-01T10:00:00Z",
+								"stargazers_count": 78000,
+								"forks_count": 11000,
+								"default_branch": "master",
+								"license": {
+									"key": "other",
+									"spdx_id": "NOASSERTION"
+								},
 								"language": "Go"
 							}
 						]
@@ -78,11 +96,40 @@ func TestClient_ProjectsByLanguage(t *testing.T) {
 			count:    1,
 			want: []app.Project{
 				{
-					ID:         23096959,
-					Name:       "go",
-					OwnerLogin: "golang",
+					ID:            23096959,
+					Name:          "go",
+					OwnerLogin:    "golang",
+					Stars:         78000,
+					Forks:         11000,
+					Description:   "The Go programming language",
+					URL:           "https://github.com/golang/go",
+					DefaultBranch: "master",
+					PushedAt:      time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC),
+					License:       "NOASSERTION",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "status ok, with filters",
+			doer: &mock.HTTPDoer{
+				Statuses: []int{http.StatusOK},
+				Bodies: [][]byte{
+					[]byte(`{"items": []}`),
 				},
 			},
+			language: "go",
+			filter: app.ProjectFilter{
+				MinStars:        100,
+				ExcludeForks:    true,
+				ExcludeArchived: true,
+				Topics:          []string{"cli", "http"},
+				PushedAfter:     time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+				License:         "mit",
+			},
+			count:   1,
+			want:    []app.Project{},
+			wantQ:   "language:go stars:>=100 fork:false archived:false topic:cli topic:http pushed:>2020-05-01 license:mit",
 			wantErr: false,
 		},
 		{
@@ -114,7 +161,10 @@ func TestClient_ProjectsByLanguage(t *testing.T) {
 			c := NewClient(tt.doer, "https://fake", "token")
 			got, err := c.ProjectsByLanguage(
 				context.Background(),
-				tt.language,
+				app.ProjectsQuery{
+					Language: tt.language,
+					Filter:   tt.filter,
+				},
 				tt.count,
 			)
 			require.Equal(t, tt.wantErr, err != nil)
@@ -126,7 +176,11 @@ func TestClient_ProjectsByLanguage(t *testing.T) {
 
 			require.Len(t, tt.doer.Responses, 1)
 			req := tt.doer.Responses[0].Request
-			assert.Equal(t, "language:"+tt.language, req.URL.Query().Get("q"))
+			wantQ := tt.wantQ
+			if wantQ == "" {
+				wantQ = "language:" + tt.language
+			}
+			assert.Equal(t, wantQ, req.URL.Query().Get("q"))
 			assert.Equal(t, "stars", req.URL.Query().Get("sort"))
 			assert.Equal(t, strconv.Itoa(tt.count), req.URL.Query().Get("per_page"))
 
@@ -135,6 +189,116 @@ func TestClient_ProjectsByLanguage(t *testing.T) {
 	}
 }
 
+func TestClient_ProjectsByLanguagePagination(t *testing.T) {
+	t.Parallel()
+
+	// Fake server has 250 projects with consecutive IDs.
+	const total = 250
+	var m sync.Mutex
+	var requests []*http.Request
+	doer := &mock.HTTPDoer{
+		DoFunc: func(r *http.Request) (*http.Response, error) {
+			m.Lock()
+			requests = append(requests, r)
+			m.Unlock()
+
+			perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+			page := 1
+			if s := r.URL.Query().Get("page"); s != "" {
+				page, _ = strconv.Atoi(s)
+			}
+
+			var items []string
+			for id := (page-1)*perPage + 1; id <= page*perPage && id <= total; id++ {
+				items = append(items, fmt.Sprintf(`{"id": %d}`, id))
+			}
+			header := http.Header{}
+			if page*perPage < total {
+				next := *r.URL
+				q := next.Query()
+				q.Set("page", strconv.Itoa(page+1))
+				next.RawQuery = q.Encode()
+				header.Set("Link", fmt.Sprintf(`<%s>; rel="next", <https://fake/last>; rel="last"`, next.String()))
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"items": [` + strings.Join(items, ",") + `]}`)),
+				Header:     header,
+				Request:    r,
+			}, nil
+		},
+	}
+	c := NewClient(doer, "https://fake", "token")
+	q := app.ProjectsQuery{Language: "go"}
+
+	tests := []struct {
+		name      string
+		count     int
+		wantCount int
+		wantPages []string
+	}{
+		{
+			name:      "single page",
+			count:     100,
+			wantCount: 100,
+			wantPages: []string{""},
+		},
+		{
+			name:      "partial last page",
+			count:     150,
+			wantCount: 150,
+			wantPages: []string{"", "2"},
+		},
+		{
+			name:      "more than available",
+			count:     1000,
+			wantCount: total,
+			wantPages: []string{"", "2", "3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.Lock()
+			requests = nil
+			m.Unlock()
+
+			got, err := c.ProjectsByLanguage(context.Background(), q, tt.count)
+			require.NoError(t, err)
+			require.Len(t, got, tt.wantCount)
+			for i, p := range got {
+				require.Equal(t, i+1, p.ID)
+			}
+
+			m.Lock()
+			defer m.Unlock()
+			var pages []string
+			for _, r := range requests {
+				assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+				pages = append(pages, r.URL.Query().Get("page"))
+				checkAPIHeaders(r, t)
+			}
+			assert.Equal(t, tt.wantPages, pages)
+		})
+	}
+
+	t.Run("projects page", func(t *testing.T) {
+		got, hasNext, err := c.ProjectsPage(context.Background(), q, 2)
+		require.NoError(t, err)
+		assert.True(t, hasNext)
+		require.Len(t, got, 100)
+		assert.Equal(t, 101, got[0].ID)
+
+		got, hasNext, err = c.ProjectsPage(context.Background(), q, 3)
+		require.NoError(t, err)
+		assert.False(t, hasNext)
+		require.Len(t, got, 50)
+
+		_, _, err = c.ProjectsPage(context.Background(), q, 11)
+		assert.Error(t, err)
+	})
+}
+
 func TestClient_StatsByProject(t *testing.T) {
 	t.Parallel()
 
@@ -149,14 +313,14 @@ func TestClient_StatsByProject(t *testing.T) {
 			"weeks": [
 				{
 					"w": 1530403200,
-					"a": 0,
-					"d": 0,
+					"a": 12,
+					"d": 4,
 					"c": 1
 				},
 				{
 					"w": 1531008000,
-					"a": 0,
-					"d": 0,
+					"a": 3,
+					"d": 1,
 					"c": 2
 				}
 			],
@@ -189,13 +353,14 @@ func TestClient_StatsByProject(t *testing.T) {
 	]`)
 
 	tests := []struct {
-		name         string
-		doer         *mock.HTTPDoer
-		projectName  string
-		owner        string
-		want         []app.ContributorStats
-		wantErr      bool
-		wantAPICalls int
+		name          string
+		doer          *mock.HTTPDoer
+		projectName   string
+		owner         string
+		want          []app.ContributorStats
+		wantErr       bool
+		wantComputing bool
+		wantAPICalls  int
 	}{
 		{
 			name:         "empty owner",
@@ -225,11 +390,27 @@ func TestClient_StatsByProject(t *testing.T) {
 			owner:       "Avik-Jain",
 			want: []app.ContributorStats{
 				{
-					Commits: 3,
+					Commits:   3,
+					Additions: 15,
+					Deletions: 5,
 					Contributor: app.Contributor{
 						ID:    15854038,
 						Login: "minderov",
 					},
+					Weeks: []app.WeeklyStats{
+						{
+							Start:     time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+							Commits:   1,
+							Additions: 12,
+							Deletions: 4,
+						},
+						{
+							Start:     time.Date(2018, 7, 8, 0, 0, 0, 0, time.UTC),
+							Commits:   2,
+							Additions: 3,
+							Deletions: 1,
+						},
+					},
 				},
 				{
 					Commits: 7,
@@ -237,6 +418,16 @@ func TestClient_StatsByProject(t *testing.T) {
 						ID:    17466938,
 						Login: "KarandikarMihir",
 					},
+					Weeks: []app.WeeklyStats{
+						{
+							Start:   time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+							Commits: 3,
+						},
+						{
+							Start:   time.Date(2018, 7, 8, 0, 0, 0, 0, time.UTC),
+							Commits: 4,
+						},
+					},
 				},
 			},
 			wantErr:      false,
@@ -268,81 +459,35 @@ func TestClient_StatsByProject(t *testing.T) {
 			wantAPICalls: 1,
 		},
 		{
-			name: "2 time 202, then valid response",
+			name: "status 202, stats are being computed",
 			doer: &mock.HTTPDoer{
 				Statuses: []int{
-					http.StatusAccepted,
 					http.StatusAccepted,
 					http.StatusOK,
 				},
 				Bodies: [][]byte{
-					{},
 					{},
 					validStatsJSON,
 				},
 			},
-			projectName: "100-Days-Of-ML-Code",
-			owner:       "Avik-Jain",
-			want: []app.ContributorStats{
-				{
-					Commits: 3,
-					Contributor: app.Contributor{
-						ID:    15854038,
-						Login: "minderov",
-					},
-				},
-				{
-					Commits: 7,
-					Contributor: app.Contributor{
-						ID:    17466938,
-						Login: "KarandikarMihir",
-					},
-				},
-			},
-			wantErr:      false,
-			wantAPICalls: 3,
-		},
-		{
-			name: "got 202 too many times",
-			doer: &mock.HTTPDoer{
-				Statuses: []int{
-					http.StatusAccepted,
-					http.StatusAccepted,
-					http.StatusAccepted,
-					http.StatusAccepted,
-					http.StatusAccepted,
-					http.StatusAccepted,
-					http.StatusAccepted,
-					http.StatusOK,
-				},
-				Bodies: [][]byte{
-					{},
-					{},
-					{},
-					{},
-					{},
-					{},
-					{},
-					validStatsJSON,
-				},
-			},
-			projectName:  "100-Days-Of-ML-Code",
-			owner:        "Avik-Jain",
-			want:         nil,
-			wantErr:      true,
-			wantAPICalls: 7,
+			projectName:   "100-Days-Of-ML-Code",
+			owner:         "Avik-Jain",
+			want:          nil,
+			wantErr:       true,
+			wantComputing: true,
+			wantAPICalls:  1,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := NewClient(tt.doer, "https://fake", "token")
-			c.acceptWaitTime = 100 * time.Millisecond
 			got, err := c.StatsByProject(
 				context.Background(),
 				tt.projectName,
 				tt.owner,
 			)
 			require.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantComputing, app.IsUpstreamComputingError(err))
 			assert.Equal(t, tt.want, got)
 
 			if tt.doer == nil {
@@ -357,7 +502,168 @@ func TestClient_StatsByProject(t *testing.T) {
 	}
 }
 
+func TestClient_StatsByProjectIfModified(t *testing.T) {
+	t.Parallel()
+
+	const etag = `"abc"`
+	const lastModified = "Thu, 05 Jul 2018 15:31:30 GMT"
+	doer := &mock.HTTPDoer{
+		DoFunc: func(r *http.Request) (*http.Response, error) {
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`[{"total": 1, "author": {"login": "person1", "id": 1}}]`)),
+				Header:     http.Header{},
+				Request:    r,
+			}
+			if r.Header.Get("If-None-Match") == etag {
+				resp.StatusCode = http.StatusNotModified
+				resp.Body = ioutil.NopCloser(strings.NewReader(""))
+			}
+			resp.Header.Set("ETag", etag)
+			resp.Header.Set("Last-Modified", lastModified)
+			return resp, nil
+		},
+	}
+	c := NewClient(doer, "https://fake", "token")
+
+	stats, v, modified, err := c.StatsByProjectIfModified(context.Background(), "go", "golang", forge.Validators{})
+	require.NoError(t, err)
+	assert.True(t, modified)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, forge.Validators{ETag: etag, LastModified: lastModified}, v)
+
+	stats, v2, modified, err := c.StatsByProjectIfModified(context.Background(), "go", "golang", v)
+	require.NoError(t, err)
+	assert.False(t, modified)
+	assert.Empty(t, stats)
+	assert.Equal(t, v, v2)
+}
+
 func checkAPIHeaders(r *http.Request, t *testing.T) {
 	assert.Equal(t, "application/vnd.github.v3+json", r.Header.Get("Accept"))
 	assert.Contains(t, r.Header.Get("Authorization"), "token ")
 }
+
+func TestClient_RateLimitError(t *testing.T) {
+	t.Parallel()
+
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	tests := []struct {
+		name          string
+		header        http.Header
+		wantExhausted bool
+	}{
+		{
+			name: "quota exhausted",
+			header: http.Header{
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{reset},
+			},
+			wantExhausted: true,
+		},
+		{
+			name: "secondary rate limit",
+			header: http.Header{
+				"Retry-After": []string{"60"},
+			},
+			wantExhausted: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &mock.HTTPDoer{
+				Statuses: []int{http.StatusForbidden},
+				Bodies:   [][]byte{nil},
+				Headers:  []http.Header{tt.header},
+			}
+			c := NewClient(doer, "https://fake", "token")
+
+			_, err := c.StatsByProject(context.Background(), "go", "golang")
+			var rateLimitErr app.RateLimitError
+			require.True(t, errors.As(err, &rateLimitErr))
+			assert.Equal(t, tt.wantExhausted, rateLimitErr.Exhausted)
+			assert.True(t, rateLimitErr.RetryAfter > 59*time.Second)
+		})
+	}
+}
+
+func TestClient_NotFoundError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown language", func(t *testing.T) {
+		doer := &mock.HTTPDoer{
+			Statuses: []int{http.StatusUnprocessableEntity},
+			Bodies: [][]byte{[]byte(`{
+				"message": "Validation Failed",
+				"errors": [{"message": "language:nolang is not a valid language", "resource": "Search", "field": "q", "code": "invalid"}]
+			}`)},
+		}
+		c := NewClient(doer, "https://fake", "token")
+
+		_, err := c.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "nolang"}, 1)
+		assert.True(t, app.IsNotFoundError(err))
+	})
+	t.Run("missing repository in search", func(t *testing.T) {
+		doer := &mock.HTTPDoer{
+			Statuses: []int{http.StatusUnprocessableEntity},
+			Bodies: [][]byte{[]byte(`{
+				"message": "Validation Failed",
+				"errors": [{
+					"message": "The listed users and repositories cannot be searched either because the resources do not exist or you do not have permission to view them.",
+					"resource": "Search", "field": "q", "code": "invalid"
+				}]
+			}`)},
+		}
+		c := NewClient(doer, "https://fake", "token")
+
+		_, err := c.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 1)
+		assert.True(t, app.IsNotFoundError(err))
+	})
+	t.Run("missing repository", func(t *testing.T) {
+		doer := &mock.HTTPDoer{
+			Statuses: []int{http.StatusNotFound},
+			Bodies:   [][]byte{[]byte(`{"message": "Not Found"}`)},
+		}
+		c := NewClient(doer, "https://fake", "token")
+
+		_, err := c.StatsByProject(context.Background(), "go", "golang")
+		assert.True(t, app.IsNotFoundError(err))
+	})
+}
+
+func TestClient_ValidationError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "malformed qualifier",
+			body: `{
+				"message": "Validation Failed",
+				"errors": [{"message": "Invalid search query: topic:a..b", "resource": "Search", "field": "q", "code": "invalid"}]
+			}`,
+		},
+		{
+			name: "no error details",
+			body: `{"message": "Validation Failed"}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &mock.HTTPDoer{
+				Statuses: []int{http.StatusUnprocessableEntity},
+				Bodies:   [][]byte{[]byte(tt.body)},
+			}
+			c := NewClient(doer, "https://fake", "token")
+
+			_, err := c.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 1)
+			assert.True(t, app.IsInvalidRequestError(err))
+			assert.False(t, app.IsNotFoundError(err))
+		})
+	}
+}
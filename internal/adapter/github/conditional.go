@@ -0,0 +1,27 @@
+package github
+
+import (
+	"net/http"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+)
+
+var _ forge.ConditionalStatsClient = &Client{}
+
+// setConditionalHeaders sets request headers for conditional request.
+func setConditionalHeaders(req *http.Request, v forge.Validators) {
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+}
+
+// validatorsFromHeader returns validators from response headers.
+func validatorsFromHeader(h http.Header) forge.Validators {
+	return forge.Validators{
+		ETag:         h.Get("ETag"),
+		LastModified: h.Get("Last-Modified"),
+	}
+}
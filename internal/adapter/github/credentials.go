@@ -0,0 +1,190 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+)
+
+// CredentialProvider provides token authorizing api requests.
+type CredentialProvider interface {
+	// Token returns auth token. Empty token means that requests are sent without authorization.
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a CredentialProvider always returning the same token, e.g. personal access token.
+type StaticToken string
+
+// Token implements CredentialProvider interface.
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// AppCredentials is a CredentialProvider authenticating as a github app installation.
+// Installation access tokens are retrieved with JWT signed by app's private key. Tokens are cached
+// and refreshed before they expire.
+// See: https://docs.github.com/en/developers/apps/building-github-apps/authenticating-with-github-apps
+type AppCredentials struct {
+	doer           forge.HTTPDoer
+	address        string
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+
+	// refreshMargin is the time before token's expiration when it's refreshed.
+	refreshMargin time.Duration
+
+	m         sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewAppCredentials creates AppCredentials instance.
+// address is github rest api address, privateKeyPEM is app's private key in PEM format.
+func NewAppCredentials(
+	doer forge.HTTPDoer,
+	address string,
+	appID int64,
+	installationID int64,
+	privateKeyPEM []byte,
+) (*AppCredentials, error) {
+	if appID <= 0 {
+		return nil, errors.New("app id must be positive")
+	}
+	if installationID <= 0 {
+		return nil, errors.New("installation id must be positive")
+	}
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	return &AppCredentials{
+		doer:           doer,
+		address:        address,
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		refreshMargin:  5 * time.Minute,
+	}, nil
+}
+
+// Token returns cached installation access token, or retrieves new one if cached token is about to expire.
+func (c *AppCredentials) Token(ctx context.Context) (string, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.token != "" && time.Now().Add(c.refreshMargin).Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	token, expiresAt, err := c.installationToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("retrieving installation token: %w", err)
+	}
+	c.token = token
+	c.expiresAt = expiresAt
+
+	return token, nil
+}
+
+// installationToken exchanges app's JWT for new installation access token.
+func (c *AppCredentials) installationToken(ctx context.Context) (string, time.Time, error) {
+	jwt, err := c.jwt(time.Now())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("creating jwt: %w", err)
+	}
+
+	u := fmt.Sprintf("%s/app/installations/%d/access_tokens", c.address, c.installationID)
+	req, err := http.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := forge.Do(ctx, c.doer, req, 1024*1024)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.Status != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("got invalid http status code: %d", resp.Status)
+	}
+
+	var tokenResp struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(resp.Body, &tokenResp); err != nil {
+		return "", time.Time{}, fmt.Errorf("unmarshalling response: %w", err)
+	}
+	if tokenResp.Token == "" {
+		return "", time.Time{}, errors.New("empty token in response")
+	}
+
+	return tokenResp.Token, tokenResp.ExpiresAt, nil
+}
+
+// jwt returns JWT identifying the app, signed with RS256 algorithm.
+func (c *AppCredentials) jwt(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+	// Issue time is set in the past to allow for clock drift. Github accepts JWTs valid for at most 10 minutes.
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": c.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("signing: %w", err)
+	}
+
+	return unsigned + "." + enc.EncodeToString(signature), nil
+}
+
+// parsePrivateKey parses RSA private key in PKCS #1 or PKCS #8 PEM format.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not RSA key")
+	}
+
+	return rsaKey, nil
+}
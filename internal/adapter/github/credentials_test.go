@@ -0,0 +1,227 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppCredentials(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	tests := []struct {
+		name       string
+		tokenTTL   time.Duration
+		status     int
+		wantTokens []string
+		wantIssued int
+		wantErr    bool
+	}{
+		{
+			name:       "token is cached",
+			tokenTTL:   time.Hour,
+			status:     http.StatusCreated,
+			wantTokens: []string{"token1", "token1", "token1"},
+			wantIssued: 1,
+		},
+		{
+			name:       "token about to expire is refreshed",
+			tokenTTL:   time.Minute,
+			status:     http.StatusCreated,
+			wantTokens: []string{"token1", "token2", "token3"},
+			wantIssued: 3,
+		},
+		{
+			name:       "token endpoint error",
+			status:     http.StatusUnauthorized,
+			wantIssued: 1,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeTokenServer(t, &key.PublicKey, 123, 456, tt.tokenTTL, tt.status)
+			defer server.Close()
+
+			credentials, err := NewAppCredentials(server.Client(), server.URL, 123, 456, keyPEM)
+			require.NoError(t, err)
+
+			if tt.wantErr {
+				_, err := credentials.Token(context.Background())
+				require.Error(t, err)
+			}
+			for _, want := range tt.wantTokens {
+				token, err := credentials.Token(context.Background())
+				require.NoError(t, err)
+				assert.Equal(t, want, token)
+			}
+			assert.Equal(t, tt.wantIssued, server.issued())
+		})
+	}
+}
+
+func TestNewAppCredentialsInvalidParams(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewAppCredentials(http.DefaultClient, "https://fake", 0, 1, nil)
+	assert.Error(t, err)
+	_, err = NewAppCredentials(http.DefaultClient, "https://fake", 1, 0, nil)
+	assert.Error(t, err)
+	_, err = NewAppCredentials(http.DefaultClient, "https://fake", 1, 1, []byte("invalid key"))
+	assert.Error(t, err)
+}
+
+func TestClientWithAppCredentials(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	server := newFakeTokenServer(t, &key.PublicKey, 123, 456, time.Hour, http.StatusCreated)
+	defer server.Close()
+
+	credentials, err := NewAppCredentials(server.Client(), server.URL, 123, 456, keyPEM)
+	require.NoError(t, err)
+	client := NewClientWithCredentials(server.Client(), server.URL, credentials)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 1)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, server.issued())
+	assert.Equal(t, []string{"token token1", "token token1"}, server.apiAuthorizations())
+}
+
+// fakeTokenServer issues installation tokens for valid app's JWTs, and serves empty search results.
+type fakeTokenServer struct {
+	*httptest.Server
+
+	m              sync.Mutex
+	tokens         int
+	authorizations []string
+}
+
+func newFakeTokenServer(
+	t *testing.T,
+	key *rsa.PublicKey,
+	appID int64,
+	installationID int64,
+	tokenTTL time.Duration,
+	status int,
+) *fakeTokenServer {
+	var s fakeTokenServer
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.m.Lock()
+		defer s.m.Unlock()
+
+		if r.URL.Path == "/search/repositories" {
+			s.authorizations = append(s.authorizations, r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"items": []}`))
+			return
+		}
+
+		if r.Method != http.MethodPost || r.URL.Path != fmt.Sprintf("/app/installations/%d/access_tokens", installationID) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.tokens++
+		if err := verifyAppJWT(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), key, appID); err != nil {
+			t.Errorf("invalid jwt: %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if status != http.StatusCreated {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":      fmt.Sprintf("token%d", s.tokens),
+			"expires_at": time.Now().Add(tokenTTL).UTC().Format(time.RFC3339),
+		})
+	}))
+
+	return &s
+}
+
+func (s *fakeTokenServer) issued() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.tokens
+}
+
+func (s *fakeTokenServer) apiAuthorizations() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append([]string{}, s.authorizations...)
+}
+
+func verifyAppJWT(jwt string, key *rsa.PublicKey, appID int64) error {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid number of parts: %d", len(parts))
+	}
+
+	enc := base64.RawURLEncoding
+	signature, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature); err != nil {
+		return fmt.Errorf("verifying signature: %w", err)
+	}
+
+	claimsJSON, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("decoding claims: %w", err)
+	}
+	var claims struct {
+		IssuedAt  int64 `json:"iat"`
+		ExpiresAt int64 `json:"exp"`
+		Issuer    int64 `json:"iss"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return fmt.Errorf("unmarshalling claims: %w", err)
+	}
+	if claims.Issuer != appID {
+		return fmt.Errorf("invalid issuer: %d", claims.Issuer)
+	}
+	now := time.Now().Unix()
+	if claims.IssuedAt > now || claims.ExpiresAt < now || claims.ExpiresAt-claims.IssuedAt > 600 {
+		return fmt.Errorf("invalid validity period: %d - %d", claims.IssuedAt, claims.ExpiresAt)
+	}
+
+	return nil
+}
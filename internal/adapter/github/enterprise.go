@@ -0,0 +1,20 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+)
+
+// EnterpriseAddresses returns addresses of rest and graphql apis of GitHub Enterprise Server with given base url.
+// See: https://docs.github.com/en/enterprise-server/rest/overview/resources-in-the-rest-api#current-version
+func EnterpriseAddresses(baseURL string) (restAddress string, graphQLAddress string, err error) {
+	u, err := forge.ParseHTTPURL(baseURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid enterprise url: %w", err)
+	}
+	base := strings.TrimSuffix(u.String(), "/")
+
+	return base + "/api/v3", base + "/api/graphql", nil
+}
@@ -0,0 +1,55 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnterpriseAddresses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		baseURL     string
+		wantREST    string
+		wantGraphQL string
+		wantErr     bool
+	}{
+		{
+			name:        "base url",
+			baseURL:     "https://github.example.com",
+			wantREST:    "https://github.example.com/api/v3",
+			wantGraphQL: "https://github.example.com/api/graphql",
+		},
+		{
+			name:        "base url with trailing slash and path",
+			baseURL:     "https://example.com/github/",
+			wantREST:    "https://example.com/github/api/v3",
+			wantGraphQL: "https://example.com/github/api/graphql",
+		},
+		{
+			name:    "no scheme",
+			baseURL: "github.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported scheme",
+			baseURL: "ftp://github.example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, graphQL, err := EnterpriseAddresses(tt.baseURL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantREST, rest)
+			assert.Equal(t, tt.wantGraphQL, graphQL)
+		})
+	}
+}
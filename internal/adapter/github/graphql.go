@@ -0,0 +1,420 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/sirupsen/logrus"
+)
+
+// GraphQLClient returns details about github projects and stats using github's GraphQL api (v4).
+// This struct is an adapter for app.ForgeClient.
+//
+// Stats are computed from projects' commit history. Concurrent StatsByProject calls are batched,
+// so a single api request retrieves history of many projects.
+type GraphQLClient struct {
+	doer        forge.HTTPDoer
+	address     string
+	credentials CredentialProvider
+
+	// batchSize is the maximum number of projects in a single request.
+	batchSize int
+	// batchWait is the time StatsByProject call waits for other calls to join the batch.
+	batchWait time.Duration
+	// maxCommits is the maximum number of latest commits per project stats are computed from.
+	maxCommits int
+
+	responseMaxSize int
+
+	l logrus.FieldLogger
+
+	m       sync.Mutex
+	pending []*graphQLStatsRequest
+	timer   *time.Timer
+}
+
+var _ app.ForgeClient = &GraphQLClient{}
+
+// NewGraphQLClient creates new github GraphQL api client.
+// authToken is required by github's GraphQL api, but is optional for api compatible servers.
+// maxCommits limits commits retrieved for project's stats, only the most recent ones are counted.
+// Truncated results are logged.
+func NewGraphQLClient(
+	doer forge.HTTPDoer,
+	address string,
+	authToken string,
+	maxCommits int,
+	l logrus.FieldLogger,
+) (*GraphQLClient, error) {
+	return NewGraphQLClientWithCredentials(doer, address, StaticToken(authToken), maxCommits, l)
+}
+
+// NewGraphQLClientWithCredentials creates new github GraphQL api client authorizing requests with tokens from given provider.
+// maxCommits is the same as in NewGraphQLClient.
+func NewGraphQLClientWithCredentials(
+	doer forge.HTTPDoer,
+	address string,
+	credentials CredentialProvider,
+	maxCommits int,
+	l logrus.FieldLogger,
+) (*GraphQLClient, error) {
+	if maxCommits < 1 {
+		return nil, errors.New("max commits must be positive")
+	}
+
+	return &GraphQLClient{
+		doer:        doer,
+		address:     address,
+		credentials: credentials,
+
+		batchSize:  20,
+		batchWait:  10 * time.Millisecond,
+		maxCommits: maxCommits,
+
+		responseMaxSize: 1024 * 1024 * 30,
+
+		l: l,
+	}, nil
+}
+
+// ProjectsByLanguage returns projects by given programming language name and filters.
+// Up to 1000 projects can be returned, more than 100 projects are retrieved page by page.
+func (c *GraphQLClient) ProjectsByLanguage(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error) {
+	if q.Language == "" {
+		return nil, app.InvalidRequestError("lanuage cannot be empty")
+	}
+	if count < 1 || count > searchMaxResults {
+		return nil, app.InvalidRequestError(fmt.Sprintf("count must be in range <1..%d>", searchMaxResults))
+	}
+
+	projects := make([]app.Project, 0, count)
+	vars := map[string]interface{}{
+		"q": searchQuery(q) + " sort:stars",
+	}
+	for len(projects) < count {
+		first := count - len(projects)
+		if first > forge.ProjectsPageSize {
+			first = forge.ProjectsPageSize
+		}
+		vars["count"] = first
+
+		var resp graphQLSearchResponse
+		if err := c.query(ctx, graphQLSearchQuery, vars, &resp); err != nil {
+			var queryErr graphQLErrors
+			if errors.As(err, &queryErr) && queryErr.notFound() {
+				return nil, app.NotFoundError(fmt.Sprintf("projects for query %s not found: %v", q.Key(), err))
+			}
+			return nil, err
+		}
+		// Github's GraphQL search doesn't report unknown language, it returns no results instead.
+		// Known language without filters always matches some projects.
+		if len(projects) == 0 && len(resp.Search.Nodes) == 0 && q.Filter.IsZero() {
+			return nil, app.NotFoundError(fmt.Sprintf("language %s not found", q.Language))
+		}
+		projects = append(projects, resp.ToProjects()...)
+
+		if !resp.Search.PageInfo.HasNextPage {
+			break
+		}
+		vars["after"] = resp.Search.PageInfo.EndCursor
+	}
+
+	return projects, nil
+}
+
+// StatsByProject returns stats by given github project params.
+// Stats are computed from latest commits of project's default branch, up to client's max commits.
+func (c *GraphQLClient) StatsByProject(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+	if name == "" {
+		return nil, app.InvalidRequestError("project's name cannot be empty")
+	}
+	if owner == "" {
+		return nil, app.InvalidRequestError("project's owner login cannot be empty")
+	}
+
+	req := graphQLStatsRequest{
+		ctx:   ctx,
+		name:  name,
+		owner: owner,
+		done:  make(chan struct{}),
+	}
+	c.enqueue(&req)
+
+	select {
+	case <-req.done:
+		return req.stats, req.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type graphQLStatsRequest struct {
+	ctx   context.Context
+	name  string
+	owner string
+	done  chan struct{}
+
+	stats []app.ContributorStats
+	err   error
+}
+
+// enqueue adds request to the pending batch. Batch is sent when it's full or after batchWait.
+func (c *GraphQLClient) enqueue(req *graphQLStatsRequest) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.pending = append(c.pending, req)
+	if len(c.pending) >= c.batchSize {
+		if c.timer != nil {
+			c.timer.Stop()
+			c.timer = nil
+		}
+		batch := c.pending
+		c.pending = nil
+		go c.runBatch(batch)
+		return
+	}
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.batchWait, c.flush)
+	}
+}
+
+func (c *GraphQLClient) flush() {
+	c.m.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.timer = nil
+	c.m.Unlock()
+
+	if len(batch) > 0 {
+		c.runBatch(batch)
+	}
+}
+
+// runBatch retrieves commit history of batched projects, page by page, and computes their stats.
+// Requests are canceled when all callers are gone.
+func (c *GraphQLClient) runBatch(batch []*graphQLStatsRequest) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for _, req := range batch {
+			select {
+			case <-req.ctx.Done():
+			case <-ctx.Done():
+				return
+			}
+		}
+		cancel()
+	}()
+
+	type state struct {
+		req     *graphQLStatsRequest
+		cursor  string
+		commits int
+		stats   map[int]*app.ContributorStats
+	}
+	active := make([]*state, 0, len(batch))
+	for _, req := range batch {
+		active = append(active, &state{
+			req:   req,
+			stats: make(map[int]*app.ContributorStats),
+		})
+	}
+	finish := func(s *state, err error) {
+		if err == nil {
+			s.req.stats = graphQLStats(s.stats)
+		}
+		s.req.err = err
+		close(s.req.done)
+	}
+
+	for len(active) > 0 {
+		var query strings.Builder
+		var params []string
+		vars := make(map[string]interface{})
+		for i, s := range active {
+			params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!, $c%d: String", i, i, i))
+			fmt.Fprintf(&query, graphQLHistoryFragment, i, i, i, i)
+			vars["o"+strconv.Itoa(i)] = s.req.owner
+			vars["n"+strconv.Itoa(i)] = s.req.name
+			if s.cursor != "" {
+				vars["c"+strconv.Itoa(i)] = s.cursor
+			}
+		}
+		q := "query(" + strings.Join(params, ", ") + ") {" + query.String() + "}"
+
+		var resp map[string]*graphQLRepository
+		err := c.query(ctx, q, vars, &resp)
+		var queryErr graphQLErrors
+		if err != nil && !errors.As(err, &queryErr) {
+			for _, s := range active {
+				finish(s, err)
+			}
+			return
+		}
+
+		var next []*state
+		for i, s := range active {
+			alias := "r" + strconv.Itoa(i)
+			// Missing repository is reported with NOT_FOUND error and null data.
+			if errs := queryErr.forPath(alias); len(errs) > 0 && !errs.notFound() {
+				finish(s, errs)
+				continue
+			}
+			repo := resp[alias]
+			if repo == nil {
+				finish(s, app.NotFoundError(fmt.Sprintf("project %s/%s not found", s.req.owner, s.req.name)))
+				continue
+			}
+			history := repo.history()
+			if history == nil {
+				// Empty repository.
+				finish(s, nil)
+				continue
+			}
+
+			truncated := false
+			for _, n := range history.Nodes {
+				if s.commits >= c.maxCommits {
+					truncated = true
+					break
+				}
+				s.commits++
+				if n.Author.User == nil {
+					continue
+				}
+				user := n.Author.User
+				st, ok := s.stats[user.DatabaseID]
+				if !ok {
+					st = &app.ContributorStats{
+						Contributor: app.Contributor{
+							ID:    user.DatabaseID,
+							Login: user.Login,
+						},
+					}
+					s.stats[user.DatabaseID] = st
+				}
+				addCommit(st, n.CommittedDate, n.Additions, n.Deletions)
+			}
+
+			if !history.PageInfo.HasNextPage && !truncated {
+				finish(s, nil)
+				continue
+			}
+			if s.commits >= c.maxCommits {
+				c.l.Warnf(
+					"github graphql client: stats of %s/%s truncated to %d most recent commits",
+					s.req.owner, s.req.name, s.commits,
+				)
+				finish(s, nil)
+				continue
+			}
+			s.cursor = history.PageInfo.EndCursor
+			next = append(next, s)
+		}
+		active = next
+	}
+}
+
+// query executes GraphQL query and unmarshals response's data into v.
+// If response contains errors, graphQLErrors is returned and data is still unmarshalled.
+func (c *GraphQLClient) query(ctx context.Context, query string, vars map[string]interface{}, v interface{}) error {
+	reqBody, err := json.Marshal(graphQLRequest{
+		Query:     query,
+		Variables: vars,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, c.address, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	token, err := c.credentials.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("getting auth token: %w", err)
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "bearer "+token)
+	}
+
+	resp, err := forge.Do(ctx, c.doer, httpReq, c.responseMaxSize)
+	if err != nil {
+		return err
+	}
+	if resp.Status != http.StatusOK {
+		if err := rateLimitError(resp.Header); err != nil {
+			return err
+		}
+		return fmt.Errorf("got invalid http status code: %d", resp.Status)
+	}
+
+	var gqlResp graphQLResponse
+	if err := json.Unmarshal(resp.Body, &gqlResp); err != nil {
+		return fmt.Errorf("unmarshalling response: %w", err)
+	}
+	if len(gqlResp.Data) > 0 && string(gqlResp.Data) != "null" {
+		if err := json.Unmarshal(gqlResp.Data, v); err != nil {
+			return fmt.Errorf("unmarshalling response data: %w", err)
+		}
+	}
+	if len(gqlResp.Errors) > 0 {
+		return gqlResp.Errors
+	}
+
+	return nil
+}
+
+// addCommit adds commit to contributor's stats, in weekly buckets starting on Sunday, like github's rest api does.
+func addCommit(st *app.ContributorStats, date time.Time, additions, deletions int) {
+	weekStart := forge.WeekStart(date)
+
+	st.Commits++
+	st.Additions += additions
+	st.Deletions += deletions
+
+	for i := range st.Weeks {
+		if st.Weeks[i].Start.Equal(weekStart) {
+			st.Weeks[i].Commits++
+			st.Weeks[i].Additions += additions
+			st.Weeks[i].Deletions += deletions
+			return
+		}
+	}
+	st.Weeks = append(st.Weeks, app.WeeklyStats{
+		Start:     weekStart,
+		Commits:   1,
+		Additions: additions,
+		Deletions: deletions,
+	})
+}
+
+// graphQLStats returns stats ordered by contributor's ID, with weeks in ascending order.
+func graphQLStats(m map[int]*app.ContributorStats) []app.ContributorStats {
+	stats := make([]app.ContributorStats, 0, len(m))
+	for _, st := range m {
+		weeks := st.Weeks
+		sort.Slice(weeks, func(i, j int) bool {
+			return weeks[i].Start.Before(weeks[j].Start)
+		})
+		stats = append(stats, *st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Contributor.ID < stats[j].Contributor.ID
+	})
+
+	return stats
+}
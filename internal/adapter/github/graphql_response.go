@@ -0,0 +1,188 @@
+package github
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+const graphQLSearchQuery = `query($q: String!, $count: Int!, $after: String) {
+  search(query: $q, type: REPOSITORY, first: $count, after: $after) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on Repository {
+        databaseId
+        name
+        owner { login }
+        stargazerCount
+        forkCount
+        description
+        url
+        defaultBranchRef { name }
+        pushedAt
+        licenseInfo { spdxId }
+      }
+    }
+  }
+}`
+
+// graphQLHistoryFragment is a query fragment retrieving page of repository's commit history.
+// Format params are repository's index in the batch.
+const graphQLHistoryFragment = `
+  r%d: repository(owner: $o%d, name: $n%d) {
+    defaultBranchRef {
+      target {
+        ... on Commit {
+          history(first: 100, after: $c%d) {
+            pageInfo { hasNextPage endCursor }
+            nodes {
+              committedDate
+              additions
+              deletions
+              author { user { databaseId login } }
+            }
+          }
+        }
+      }
+    }
+  }`
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors graphQLErrors   `json:"errors"`
+}
+
+type graphQLError struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// graphQLErrors is the list of errors returned in GraphQL response.
+type graphQLErrors []graphQLError
+
+func (e graphQLErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Message)
+	}
+	return "graphql errors: " + strings.Join(msgs, "; ")
+}
+
+// forPath returns errors concerning given top level field, or errors not concerning any field.
+// Returns nil if there are no such errors.
+func (e graphQLErrors) forPath(field string) graphQLErrors {
+	var errs graphQLErrors
+	for _, err := range e {
+		if len(err.Path) == 0 || err.Path[0] == field {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// notFound tells if all errors are caused by missing resources.
+func (e graphQLErrors) notFound() bool {
+	for _, err := range e {
+		if err.Type != "NOT_FOUND" {
+			return false
+		}
+	}
+	return len(e) > 0
+}
+
+type graphQLSearchResponse struct {
+	Search struct {
+		PageInfo graphQLPageInfo     `json:"pageInfo"`
+		Nodes    []graphQLSearchNode `json:"nodes"`
+	} `json:"search"`
+}
+
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphQLSearchNode struct {
+	DatabaseID int    `json:"databaseId"`
+	Name       string `json:"name"`
+	Owner      struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Stars            int    `json:"stargazerCount"`
+	Forks            int    `json:"forkCount"`
+	Description      string `json:"description"`
+	URL              string `json:"url"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	PushedAt    time.Time `json:"pushedAt"`
+	LicenseInfo *struct {
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+}
+
+func (s graphQLSearchResponse) ToProjects() []app.Project {
+	ps := make([]app.Project, 0, len(s.Search.Nodes))
+	for _, n := range s.Search.Nodes {
+		p := app.Project{
+			ID:          n.DatabaseID,
+			Name:        n.Name,
+			OwnerLogin:  n.Owner.Login,
+			Stars:       n.Stars,
+			Forks:       n.Forks,
+			Description: n.Description,
+			URL:         n.URL,
+			PushedAt:    n.PushedAt.UTC(),
+		}
+		if n.DefaultBranchRef != nil {
+			p.DefaultBranch = n.DefaultBranchRef.Name
+		}
+		if n.LicenseInfo != nil {
+			p.License = n.LicenseInfo.SPDXID
+		}
+		ps = append(ps, p)
+	}
+
+	return ps
+}
+
+type graphQLRepository struct {
+	DefaultBranchRef *struct {
+		Target struct {
+			History *graphQLHistory `json:"history"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+type graphQLHistory struct {
+	PageInfo graphQLPageInfo `json:"pageInfo"`
+	Nodes    []graphQLCommit `json:"nodes"`
+}
+
+type graphQLCommit struct {
+	CommittedDate time.Time `json:"committedDate"`
+	Additions     int       `json:"additions"`
+	Deletions     int       `json:"deletions"`
+	Author        struct {
+		User *struct {
+			DatabaseID int    `json:"databaseId"`
+			Login      string `json:"login"`
+		} `json:"user"`
+	} `json:"author"`
+}
+
+// history returns repository's commit history page, or nil if repository is empty.
+func (r *graphQLRepository) history() *graphQLHistory {
+	if r.DefaultBranchRef == nil {
+		return nil
+	}
+	return r.DefaultBranchRef.Target.History
+}
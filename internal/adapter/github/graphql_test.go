@@ -0,0 +1,530 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLClient_ProjectsByLanguage(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeGraphQLServer(t)
+	defer server.Close()
+
+	client, err := NewGraphQLClient(server.Client(), server.URL, "token", 1000, logrus.New())
+	require.NoError(t, err)
+
+	_, err = client.ProjectsByLanguage(context.Background(), app.ProjectsQuery{}, 1)
+	assert.True(t, app.IsInvalidRequestError(err))
+	_, err = client.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 1001)
+	assert.True(t, app.IsInvalidRequestError(err))
+
+	got, err := client.ProjectsByLanguage(
+		context.Background(),
+		app.ProjectsQuery{
+			Language: "go",
+			Filter: app.ProjectFilter{
+				MinStars: 10,
+			},
+		},
+		2,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []app.Project{
+		{
+			ID:            1,
+			Name:          "project1",
+			OwnerLogin:    "owner1",
+			Stars:         100,
+			Forks:         10,
+			Description:   "first",
+			URL:           "https://github.com/owner1/project1",
+			DefaultBranch: "main",
+			PushedAt:      time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC),
+			License:       "MIT",
+		},
+		{
+			ID:         2,
+			Name:       "project2",
+			OwnerLogin: "owner2",
+			Stars:      50,
+		},
+	}, got)
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "language:go stars:>=10 sort:stars", requests[0].Variables["q"])
+	assert.Equal(t, float64(2), requests[0].Variables["count"])
+	assert.Equal(t, "bearer token", server.authHeader)
+}
+
+func TestGraphQLClient_ProjectsByLanguagePagination(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeGraphQLServer(t)
+	defer server.Close()
+	for i := 1; i <= 150; i++ {
+		server.projects = append(server.projects, map[string]interface{}{
+			"databaseId": i,
+			"name":       "project" + strconv.Itoa(i),
+		})
+	}
+
+	client, err := NewGraphQLClient(server.Client(), server.URL, "", 1000, logrus.New())
+	require.NoError(t, err)
+	got, err := client.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 120)
+	require.NoError(t, err)
+	require.Len(t, got, 120)
+	assert.Equal(t, 1, got[0].ID)
+	assert.Equal(t, 120, got[119].ID)
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, float64(100), requests[0].Variables["count"])
+	assert.Nil(t, requests[0].Variables["after"])
+	assert.Equal(t, float64(20), requests[1].Variables["count"])
+	assert.Equal(t, "100", requests[1].Variables["after"])
+}
+
+func TestGraphQLClient_ProjectsByLanguageNotFound(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not found error", func(t *testing.T) {
+		server := newFakeGraphQLServer(t)
+		defer server.Close()
+		server.searchErrors = []interface{}{
+			map[string]interface{}{"type": "NOT_FOUND", "path": []string{"search"}, "message": "Not found"},
+		}
+
+		client, err := NewGraphQLClient(server.Client(), server.URL, "", 1000, logrus.New())
+		require.NoError(t, err)
+		_, err = client.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 1)
+		assert.True(t, app.IsNotFoundError(err))
+	})
+	t.Run("other errors", func(t *testing.T) {
+		server := newFakeGraphQLServer(t)
+		defer server.Close()
+		server.searchErrors = []interface{}{
+			map[string]interface{}{"type": "INTERNAL", "message": "Something went wrong"},
+		}
+
+		client, err := NewGraphQLClient(server.Client(), server.URL, "", 1000, logrus.New())
+		require.NoError(t, err)
+		_, err = client.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 1)
+		assert.Error(t, err)
+		assert.False(t, app.IsNotFoundError(err))
+	})
+	t.Run("unknown language", func(t *testing.T) {
+		server := newFakeGraphQLServer(t)
+		defer server.Close()
+		server.projects = []interface{}{}
+
+		client, err := NewGraphQLClient(server.Client(), server.URL, "", 1000, logrus.New())
+		require.NoError(t, err)
+		_, err = client.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "nolang"}, 1)
+		assert.True(t, app.IsNotFoundError(err))
+
+		// Filters can exclude all projects of known language.
+		got, err := client.ProjectsByLanguage(
+			context.Background(),
+			app.ProjectsQuery{Language: "go", Filter: app.ProjectFilter{MinStars: 1000000}},
+			1,
+		)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
+
+func TestGraphQLClient_StatsByProject(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeGraphQLServer(t)
+	defer server.Close()
+
+	// Commits from 2020-01-05 (sunday) on, one every 12 hours.
+	start := time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)
+	var bigHistory []fakeCommit
+	for i := 0; i < 150; i++ {
+		c := fakeCommit{
+			date:      start.Add(time.Duration(i) * 12 * time.Hour),
+			additions: 1,
+		}
+		if i%2 == 0 {
+			c.userID = 1
+			c.login = "even"
+		} else {
+			c.userID = 2
+			c.login = "odd"
+		}
+		bigHistory = append(bigHistory, c)
+	}
+	server.repos = map[string][]fakeCommit{
+		"owner/big": bigHistory,
+		"owner/small": {
+			{userID: 3, login: "c3", date: start, additions: 5, deletions: 2},
+			{userID: 3, login: "c3", date: start.Add(24 * time.Hour), additions: 1},
+			{date: start},
+		},
+		"owner/empty": nil,
+	}
+
+	client, err := NewGraphQLClient(server.Client(), server.URL, "", 1000, logrus.New())
+	require.NoError(t, err)
+	client.batchSize = 4
+	client.batchWait = time.Minute
+
+	type result struct {
+		stats []app.ContributorStats
+		err   error
+	}
+	results := make(map[string]chan result)
+	for _, name := range []string{"big", "small", "empty", "missing"} {
+		ch := make(chan result, 1)
+		results[name] = ch
+		go func(name string) {
+			stats, err := client.StatsByProject(context.Background(), name, "owner")
+			ch <- result{stats, err}
+		}(name)
+	}
+
+	big := <-results["big"]
+	require.NoError(t, big.err)
+	require.Len(t, big.stats, 2)
+	assert.Equal(t, app.Contributor{ID: 1, Login: "even"}, big.stats[0].Contributor)
+	assert.Equal(t, 75, big.stats[0].Commits)
+	assert.Equal(t, 75, big.stats[0].Additions)
+	assert.Equal(t, app.Contributor{ID: 2, Login: "odd"}, big.stats[1].Contributor)
+	assert.Equal(t, 75, big.stats[1].Commits)
+	// 150 commits in 75 days, 7 commits per user in each week.
+	require.Len(t, big.stats[0].Weeks, 11)
+	assert.Equal(t, app.WeeklyStats{Start: start, Commits: 7, Additions: 7}, big.stats[0].Weeks[0])
+	assert.Equal(t, start.AddDate(0, 0, 70), big.stats[0].Weeks[10].Start)
+
+	small := <-results["small"]
+	require.NoError(t, small.err)
+	assert.Equal(t, []app.ContributorStats{
+		{
+			Contributor: app.Contributor{ID: 3, Login: "c3"},
+			Commits:     2,
+			Additions:   6,
+			Deletions:   2,
+			Weeks: []app.WeeklyStats{
+				{Start: start, Commits: 2, Additions: 6, Deletions: 2},
+			},
+		},
+	}, small.stats)
+
+	empty := <-results["empty"]
+	require.NoError(t, empty.err)
+	assert.Empty(t, empty.stats)
+
+	missing := <-results["missing"]
+	assert.True(t, app.IsNotFoundError(missing.err))
+
+	// First request for all projects, second one for the next page of the big project's history.
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, 4, strings.Count(requests[0].Query, "repository("))
+	assert.Equal(t, 1, strings.Count(requests[1].Query, "repository("))
+	assert.Equal(t, "100", requests[1].Variables["c0"])
+}
+
+func TestGraphQLClient_StatsByProjectMaxCommits(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewGraphQLClient(http.DefaultClient, "", "", 0, logrus.New())
+	assert.Error(t, err)
+
+	start := time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)
+	history := make([]fakeCommit, 250)
+	for i := range history {
+		history[i] = fakeCommit{userID: 1, login: "c1", date: start.Add(time.Duration(i) * time.Hour)}
+	}
+
+	tests := []struct {
+		name        string
+		maxCommits  int
+		wantCommits int
+		wantWarning bool
+	}{
+		{
+			name:        "cap within page",
+			maxCommits:  120,
+			wantCommits: 120,
+			wantWarning: true,
+		},
+		{
+			name:        "cap at page end",
+			maxCommits:  200,
+			wantCommits: 200,
+			wantWarning: true,
+		},
+		{
+			name:        "whole history",
+			maxCommits:  250,
+			wantCommits: 250,
+			wantWarning: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newFakeGraphQLServer(t)
+			defer server.Close()
+			server.repos = map[string][]fakeCommit{"owner/big": history}
+
+			l, hook := test.NewNullLogger()
+			client, err := NewGraphQLClient(server.Client(), server.URL, "", tt.maxCommits, l)
+			require.NoError(t, err)
+			client.batchWait = time.Millisecond
+
+			stats, err := client.StatsByProject(context.Background(), "big", "owner")
+			require.NoError(t, err)
+			require.Len(t, stats, 1)
+			assert.Equal(t, tt.wantCommits, stats[0].Commits)
+			if tt.wantWarning {
+				require.Len(t, hook.AllEntries(), 1)
+				assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+			} else {
+				assert.Empty(t, hook.AllEntries())
+			}
+		})
+	}
+}
+
+func TestGraphQLClient_StatsByProjectErrors(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeGraphQLServer(t)
+	defer server.Close()
+	server.status = http.StatusBadGateway
+
+	client, err := NewGraphQLClient(server.Client(), server.URL, "", 1000, logrus.New())
+	require.NoError(t, err)
+	client.batchWait = time.Millisecond
+
+	_, err = client.StatsByProject(context.Background(), "", "owner")
+	assert.True(t, app.IsInvalidRequestError(err))
+	_, err = client.StatsByProject(context.Background(), "name", "")
+	assert.True(t, app.IsInvalidRequestError(err))
+
+	_, err = client.StatsByProject(context.Background(), "name", "owner")
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.StatsByProject(ctx, "name", "owner")
+	assert.Equal(t, context.Canceled, err)
+}
+
+type fakeCommit struct {
+	userID    int
+	login     string
+	date      time.Time
+	additions int
+	deletions int
+}
+
+// fakeGraphQLServer serves subset of github's GraphQL api used by GraphQLClient.
+type fakeGraphQLServer struct {
+	*httptest.Server
+	t      *testing.T
+	status int
+	// repos maps "owner/name" to repository's commit history, oldest first.
+	repos map[string][]fakeCommit
+	// projects holds search results.
+	projects []interface{}
+	// searchErrors are returned instead of search results, if not empty.
+	searchErrors []interface{}
+
+	m          sync.Mutex
+	requests   []graphQLRequest
+	authHeader string
+}
+
+func newFakeGraphQLServer(t *testing.T) *fakeGraphQLServer {
+	s := fakeGraphQLServer{
+		t:      t,
+		status: http.StatusOK,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return &s
+}
+
+func (s *fakeGraphQLServer) Requests() []graphQLRequest {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]graphQLRequest{}, s.requests...)
+}
+
+func (s *fakeGraphQLServer) handle(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.t.Errorf("decoding request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.m.Lock()
+	s.requests = append(s.requests, req)
+	s.authHeader = r.Header.Get("Authorization")
+	s.m.Unlock()
+
+	if s.status != http.StatusOK {
+		w.WriteHeader(s.status)
+		return
+	}
+
+	var resp interface{}
+	if strings.Contains(req.Query, "search(") {
+		resp = s.search(req.Variables)
+	} else {
+		resp = s.histories(req.Variables)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// search returns page of fake projects. If there are no fake projects, returns two example projects.
+func (s *fakeGraphQLServer) search(vars map[string]interface{}) interface{} {
+	if len(s.searchErrors) > 0 {
+		return map[string]interface{}{
+			"data":   nil,
+			"errors": s.searchErrors,
+		}
+	}
+
+	projects := s.projects
+	if projects == nil {
+		projects = []interface{}{
+			map[string]interface{}{
+				"databaseId":       1,
+				"name":             "project1",
+				"owner":            map[string]interface{}{"login": "owner1"},
+				"stargazerCount":   100,
+				"forkCount":        10,
+				"description":      "first",
+				"url":              "https://github.com/owner1/project1",
+				"defaultBranchRef": map[string]interface{}{"name": "main"},
+				"pushedAt":         "2020-05-01T10:00:00Z",
+				"licenseInfo":      map[string]interface{}{"spdxId": "MIT"},
+			},
+			map[string]interface{}{
+				"databaseId":       2,
+				"name":             "project2",
+				"owner":            map[string]interface{}{"login": "owner2"},
+				"stargazerCount":   50,
+				"defaultBranchRef": nil,
+				"licenseInfo":      nil,
+			},
+		}
+	}
+
+	offset := 0
+	if after, ok := vars["after"].(string); ok {
+		offset, _ = strconv.Atoi(after)
+	}
+	count, _ := vars["count"].(float64)
+	end := offset + int(count)
+	if end > len(projects) {
+		end = len(projects)
+	}
+
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"search": map[string]interface{}{
+				"pageInfo": map[string]interface{}{
+					"hasNextPage": end < len(projects),
+					"endCursor":   strconv.Itoa(end),
+				},
+				"nodes": projects[offset:end],
+			},
+		},
+	}
+}
+
+// histories returns pages of commit histories of 100 commits, newest first.
+func (s *fakeGraphQLServer) histories(vars map[string]interface{}) interface{} {
+	data := make(map[string]interface{})
+	var errs []interface{}
+	for i := 0; ; i++ {
+		owner, ok := vars["o"+strconv.Itoa(i)].(string)
+		if !ok {
+			break
+		}
+		name, _ := vars["n"+strconv.Itoa(i)].(string)
+		alias := "r" + strconv.Itoa(i)
+
+		history, ok := s.repos[owner+"/"+name]
+		if !ok {
+			data[alias] = nil
+			errs = append(errs, map[string]interface{}{
+				"type":    "NOT_FOUND",
+				"path":    []string{alias},
+				"message": fmt.Sprintf("Could not resolve to a Repository with the name '%s/%s'.", owner, name),
+			})
+			continue
+		}
+		if len(history) == 0 {
+			data[alias] = map[string]interface{}{"defaultBranchRef": nil}
+			continue
+		}
+
+		offset := 0
+		if c, ok := vars["c"+strconv.Itoa(i)].(string); ok {
+			offset, _ = strconv.Atoi(c)
+		}
+		end := offset + 100
+		if end > len(history) {
+			end = len(history)
+		}
+		var nodes []interface{}
+		for j := offset; j < end; j++ {
+			c := history[len(history)-1-j]
+			var user interface{}
+			if c.userID != 0 {
+				user = map[string]interface{}{"databaseId": c.userID, "login": c.login}
+			}
+			nodes = append(nodes, map[string]interface{}{
+				"committedDate": c.date.Format(time.RFC3339),
+				"additions":     c.additions,
+				"deletions":     c.deletions,
+				"author":        map[string]interface{}{"user": user},
+			})
+		}
+		data[alias] = map[string]interface{}{
+			"defaultBranchRef": map[string]interface{}{
+				"target": map[string]interface{}{
+					"history": map[string]interface{}{
+						"pageInfo": map[string]interface{}{
+							"hasNextPage": end < len(history),
+							"endCursor":   strconv.Itoa(end),
+						},
+						"nodes": nodes,
+					},
+				},
+			},
+		}
+	}
+
+	resp := map[string]interface{}{"data": data}
+	if len(errs) > 0 {
+		resp["errors"] = errs
+	}
+	return resp
+}
@@ -1,6 +1,9 @@
 package github
 
 import (
+	"strings"
+	"time"
+
 	"github.com/m-zajac/goprojectdemo/internal/app"
 )
 
@@ -9,23 +12,44 @@ type searchResponse struct {
 }
 
 type searchResponseItem struct {
-	ID    int                     `json:"id"`
-	Name  string                  `json:"name"`
-	Owner searchResponseItemOwner `json:"owner"`
+	ID            int                        `json:"id"`
+	Name          string                     `json:"name"`
+	Owner         searchResponseItemOwner    `json:"owner"`
+	Stars         int                        `json:"stargazers_count"`
+	Forks         int                        `json:"forks_count"`
+	Description   string                     `json:"description"`
+	URL           string                     `json:"html_url"`
+	DefaultBranch string                     `json:"default_branch"`
+	PushedAt      time.Time                  `json:"pushed_at"`
+	License       *searchResponseItemLicense `json:"license"`
 }
 
 type searchResponseItemOwner struct {
 	Login string `json:"login"`
 }
 
+type searchResponseItemLicense struct {
+	SPDXID string `json:"spdx_id"`
+}
+
 func (s searchResponse) ToProjects() []app.Project {
 	ps := make([]app.Project, 0, len(s.Items))
 	for _, i := range s.Items {
-		ps = append(ps, app.Project{
-			ID:         i.ID,
-			Name:       i.Name,
-			OwnerLogin: i.Owner.Login,
-		})
+		p := app.Project{
+			ID:            i.ID,
+			Name:          i.Name,
+			OwnerLogin:    i.Owner.Login,
+			Stars:         i.Stars,
+			Forks:         i.Forks,
+			Description:   i.Description,
+			URL:           i.URL,
+			DefaultBranch: i.DefaultBranch,
+			PushedAt:      i.PushedAt.UTC(),
+		}
+		if i.License != nil {
+			p.License = i.License.SPDXID
+		}
+		ps = append(ps, p)
 	}
 
 	return ps
@@ -34,6 +58,7 @@ func (s searchResponse) ToProjects() []app.Project {
 type statsResponse []struct {
 	Author statsResponseAuthor `json:"author"`
 	Total  int                 `json:"total"`
+	Weeks  []statsResponseWeek `json:"weeks"`
 }
 
 type statsResponseAuthor struct {
@@ -41,17 +66,57 @@ type statsResponseAuthor struct {
 	Login string `json:"login"`
 }
 
+type statsResponseWeek struct {
+	Start     int64 `json:"w"`
+	Additions int   `json:"a"`
+	Deletions int   `json:"d"`
+	Commits   int   `json:"c"`
+}
+
 func (s statsResponse) ToStats() []app.ContributorStats {
 	ss := make([]app.ContributorStats, 0, len(s))
 	for _, el := range s {
-		ss = append(ss, app.ContributorStats{
+		stats := app.ContributorStats{
 			Contributor: app.Contributor{
 				ID:    el.Author.ID,
 				Login: el.Author.Login,
 			},
 			Commits: el.Total,
-		})
+		}
+		for _, w := range el.Weeks {
+			// Github returns every week since project creation, skip empty ones.
+			if w.Commits == 0 && w.Additions == 0 && w.Deletions == 0 {
+				continue
+			}
+			stats.Additions += w.Additions
+			stats.Deletions += w.Deletions
+			stats.Weeks = append(stats.Weeks, app.WeeklyStats{
+				Start:     time.Unix(w.Start, 0).UTC(),
+				Commits:   w.Commits,
+				Additions: w.Additions,
+				Deletions: w.Deletions,
+			})
+		}
+		ss = append(ss, stats)
 	}
 
 	return ss
 }
+
+// validationResponse is the body of response with status 422.
+type validationResponse struct {
+	Message string                    `json:"message"`
+	Errors  []validationResponseError `json:"errors"`
+}
+
+type validationResponseError struct {
+	Message string `json:"message"`
+}
+
+// notFound tells if error is caused by unknown language or missing repositories, and not by malformed request.
+func (e validationResponseError) notFound() bool {
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "language") ||
+		strings.Contains(msg, "do not exist") ||
+		strings.Contains(msg, "does not exist")
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"testing"
+	"time"
 
 	"github.com/m-zajac/goprojectdemo/internal/app"
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,15 @@ func Test_searchResponse_ToProjects(t *testing.T) {
 						Owner: searchResponseItemOwner{
 							Login: "y",
 						},
+						Stars:         100,
+						Forks:         10,
+						Description:   "desc",
+						URL:           "https://github.com/y/x",
+						DefaultBranch: "main",
+						PushedAt:      time.Date(2020, 5, 1, 12, 0, 0, 0, time.FixedZone("", 3600)),
+						License: &searchResponseItemLicense{
+							SPDXID: "MIT",
+						},
 					},
 					{
 						ID:   2,
@@ -40,9 +50,16 @@ func Test_searchResponse_ToProjects(t *testing.T) {
 			},
 			want: []app.Project{
 				{
-					ID:         1,
-					Name:       "x",
-					OwnerLogin: "y",
+					ID:            1,
+					Name:          "x",
+					OwnerLogin:    "y",
+					Stars:         100,
+					Forks:         10,
+					Description:   "desc",
+					URL:           "https://github.com/y/x",
+					DefaultBranch: "main",
+					PushedAt:      time.Date(2020, 5, 1, 11, 0, 0, 0, time.UTC),
+					License:       "MIT",
 				},
 				{
 					ID:         2,
@@ -87,6 +104,23 @@ func Test_statsResponse_ToStats(t *testing.T) {
 						Login: "y",
 					},
 					Total: 4,
+					Weeks: []statsResponseWeek{
+						{
+							Start:     1530403200,
+							Additions: 10,
+							Deletions: 3,
+							Commits:   1,
+						},
+						{
+							Start:     1531008000,
+							Additions: 5,
+							Deletions: 7,
+							Commits:   3,
+						},
+						{
+							Start: 1531612800,
+						},
+					},
 				},
 			},
 			want: []app.ContributorStats{
@@ -98,11 +132,27 @@ func Test_statsResponse_ToStats(t *testing.T) {
 					},
 				},
 				{
-					Commits: 4,
+					Commits:   4,
+					Additions: 15,
+					Deletions: 10,
 					Contributor: app.Contributor{
 						ID:    3,
 						Login: "y",
 					},
+					Weeks: []app.WeeklyStats{
+						{
+							Start:     time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+							Commits:   1,
+							Additions: 10,
+							Deletions: 3,
+						},
+						{
+							Start:     time.Date(2018, 7, 8, 0, 0, 0, 0, time.UTC),
+							Commits:   3,
+							Additions: 5,
+							Deletions: 7,
+						},
+					},
 				},
 			},
 		},
@@ -0,0 +1,240 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+const (
+	// pageSize is the maximum number of items returned by gitlab api in a single page.
+	pageSize = 100
+	// projectsMaxResults is the maximum number of projects returned for a single query.
+	projectsMaxResults = 1000
+	// projectsMaxPages limits pages retrieved for a single query, when projects are filtered out client-side.
+	projectsMaxPages = 20
+)
+
+// Client returns details about gitlab projects and stats.
+// This struct is an adapter for app.ForgeClient.
+type Client struct {
+	doer    forge.HTTPDoer
+	address string
+	token   string
+
+	// emailIDs identifies contributors by their emails.
+	emailIDs *forge.EmailIDs
+
+	projectsResponseMaxSize int
+	statsResponseMaxSize    int
+}
+
+var _ app.ForgeClient = &Client{}
+
+// NewClient creates new gitlab client. address is gitlab api address, e.g. "https://gitlab.com/api/v4".
+// authToken is optional, without it only public projects are available.
+func NewClient(doer forge.HTTPDoer, address string, authToken string) *Client {
+	return &Client{
+		doer:    doer,
+		address: strings.TrimSuffix(address, "/"),
+		token:   authToken,
+
+		emailIDs: forge.NewEmailIDs(),
+
+		projectsResponseMaxSize: 1024 * 1024 * 10,
+		statsResponseMaxSize:    1024 * 1024 * 10,
+	}
+}
+
+// ProjectsByLanguage returns projects by given programming language name and filters, ordered by the number of stars.
+// Up to 1000 projects can be returned, more than 100 projects are retrieved page by page.
+//
+// Gitlab doesn't filter projects by stars and forks, these filters are applied to retrieved pages.
+// License filter is not supported.
+func (c *Client) ProjectsByLanguage(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error) {
+	if q.Language == "" {
+		return nil, app.InvalidRequestError("lanuage cannot be empty")
+	}
+	if count < 1 || count > projectsMaxResults {
+		return nil, app.InvalidRequestError(fmt.Sprintf("count must be in range <1..%d>", projectsMaxResults))
+	}
+	if q.Filter.License != "" {
+		return nil, app.InvalidRequestError("license filter is not supported by gitlab")
+	}
+
+	perPage := count
+	if perPage > pageSize || q.Filter.ExcludeForks {
+		perPage = pageSize
+	}
+	u, err := c.projectsURL(q, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]app.Project, 0, count)
+	for pages := 0; u != "" && len(projects) < count && pages < projectsMaxPages; pages++ {
+		var page projectsResponse
+		page, u, err = c.projectsPage(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page {
+			// Projects are ordered by stars, none of the next ones has enough of them.
+			if p.Stars < q.Filter.MinStars {
+				u = ""
+				break
+			}
+			if q.Filter.ExcludeForks && p.ForkedFrom != nil {
+				continue
+			}
+			projects = append(projects, p.ToProject())
+		}
+	}
+	if len(projects) > count {
+		projects = projects[:count]
+	}
+
+	return projects, nil
+}
+
+// projectsURL returns url of the first page of projects matching given query.
+// See: https://docs.gitlab.com/ee/api/projects.html#list-all-projects
+func (c *Client) projectsURL(q app.ProjectsQuery, perPage int) (string, error) {
+	u, err := url.Parse(c.address + "/projects")
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+
+	v := make(url.Values)
+	v.Set("with_programming_language", q.Language)
+	v.Set("order_by", "star_count")
+	v.Set("sort", "desc")
+	v.Set("per_page", strconv.Itoa(perPage))
+
+	f := q.Filter
+	if f.ExcludeArchived {
+		v.Set("archived", "false")
+	}
+	if len(f.Topics) > 0 {
+		v.Set("topic", strings.Join(f.Topics, ","))
+	}
+	if !f.PushedAfter.IsZero() {
+		// Gitlab doesn't expose push time, last activity is the closest equivalent.
+		v.Set("last_activity_after", f.PushedAfter.UTC().Format(time.RFC3339))
+	}
+	u.RawQuery = v.Encode()
+
+	return u.String(), nil
+}
+
+// projectsPage returns projects from page under given url, and url of the next page if there is one.
+func (c *Client) projectsPage(ctx context.Context, u string) (projectsResponse, string, error) {
+	resp, err := c.get(ctx, u, c.projectsResponseMaxSize)
+	if err != nil {
+		return nil, "", fmt.Errorf("making http request: %w", err)
+	}
+
+	var page projectsResponse
+	if err := json.Unmarshal(resp.Body, &page); err != nil {
+		return nil, "", fmt.Errorf("unmarshalling response: %w", err)
+	}
+
+	return page, nextPageURL(u, resp.Header), nil
+}
+
+// StatsByProject returns stats of project's contributors.
+//
+// Gitlab identifies contributors by commit author's name and email, instead of user accounts.
+// Contributor's login is the author's name, and id is assigned to the email, see forge.EmailIDs.
+// Weekly activity is not available, so the service rejects contributors queries with time window for gitlab.
+// See: https://docs.gitlab.com/ee/api/repositories.html#contributors
+func (c *Client) StatsByProject(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+	if name == "" {
+		return nil, app.InvalidRequestError("project's name cannot be empty")
+	}
+	if owner == "" {
+		return nil, app.InvalidRequestError("project's owner login cannot be empty")
+	}
+
+	// Project is identified by url encoded path with namespace.
+	u, err := url.Parse(fmt.Sprintf(
+		"%s/projects/%s/repository/contributors",
+		c.address,
+		url.PathEscape(owner+"/"+name),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	v := make(url.Values)
+	v.Set("order_by", "commits")
+	v.Set("sort", "desc")
+	v.Set("per_page", strconv.Itoa(pageSize))
+	u.RawQuery = v.Encode()
+
+	var contributors contributorsResponse
+	for next := u.String(); next != ""; {
+		resp, err := c.get(ctx, next, c.statsResponseMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("making http request: %w", err)
+		}
+
+		var page contributorsResponse
+		if err := json.Unmarshal(resp.Body, &page); err != nil {
+			return nil, fmt.Errorf("unmarshalling response: %w", err)
+		}
+		contributors = append(contributors, page...)
+		next = nextPageURL(next, resp.Header)
+	}
+
+	return contributors.ToStats(c.emailIDs), nil
+}
+
+func (c *Client) get(ctx context.Context, u string, maxBytes int) (*forge.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := forge.Do(ctx, c.doer, req, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := forge.StatusError(req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// nextPageURL returns url of the next page after the one under u, or empty string if there's no next page.
+// Page number from X-Next-Page header is preferred over Link header, as links point to gitlab's external url,
+// which can differ from configured api address.
+// See: https://docs.gitlab.com/ee/api/rest/#pagination
+func nextPageURL(u string, h http.Header) string {
+	page := h.Get("X-Next-Page")
+	if page == "" {
+		return forge.NextPageURL(h)
+	}
+
+	next, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	v := next.Query()
+	v.Set("page", page)
+	next.RawQuery = v.Encode()
+
+	return next.String()
+}
@@ -0,0 +1,446 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitlab serves projects and contributors of a fake gitlab instance, two items per page.
+// Next page is announced in X-Next-Page header, and in Link header pointing to gitlab's external url,
+// which is not reachable from tests. If linkOnly is set, Link header points to the fake server and
+// X-Next-Page is omitted.
+type fakeGitlab struct {
+	projects     []string
+	contributors []string
+	linkOnly     bool
+
+	m        sync.Mutex
+	requests []*http.Request
+}
+
+func (f *fakeGitlab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.m.Lock()
+	f.requests = append(f.requests, r)
+	f.m.Unlock()
+
+	var items []string
+	switch r.URL.EscapedPath() {
+	case "/api/v4/projects":
+		items = f.projects
+	case "/api/v4/projects/group%2Fsubgroup%2Fproject/repository/contributors":
+		items = f.contributors
+	case "/api/v4/projects/group%2Flimited/repository/contributors":
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	case "/api/v4/projects/group%2Fbroken/repository/contributors":
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	page := 1
+	fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+	start, end := (page-1)*2, page*2
+	if end >= len(items) {
+		end = len(items)
+		w.Header().Set("X-Next-Page", "")
+	} else {
+		q := r.URL.Query()
+		q.Set("page", fmt.Sprint(page+1))
+		host := "gitlab.external.invalid"
+		if f.linkOnly {
+			host = r.Host
+		} else {
+			w.Header().Set("X-Next-Page", fmt.Sprint(page+1))
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?%s>; rel="next"`, host, r.URL.EscapedPath(), q.Encode()))
+	}
+	if start > end {
+		start = end
+	}
+
+	fmt.Fprint(w, "[")
+	for i, item := range items[start:end] {
+		if i > 0 {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprint(w, item)
+	}
+	fmt.Fprint(w, "]")
+}
+
+func gitlabProject(id int, stars int, fork bool) string {
+	var forkedFrom string
+	if fork {
+		forkedFrom = `,"forked_from_project":{"id":1}`
+	}
+	return fmt.Sprintf(
+		`{"id":%d,"path":"project%d","namespace":{"full_path":"group/subgroup"},"star_count":%d,"forks_count":1,`+
+			`"description":"Project %d","web_url":"https://gitlab.com/group/subgroup/project%d","default_branch":"main",`+
+			`"last_activity_at":"2020-05-01T10:00:00.000Z"%s}`,
+		id, id, stars, id, id, forkedFrom,
+	)
+}
+
+func TestClient_ProjectsByLanguage(t *testing.T) {
+	t.Parallel()
+
+	projects := []string{
+		gitlabProject(1, 50, false),
+		gitlabProject(2, 40, true),
+		gitlabProject(3, 30, false),
+		gitlabProject(4, 20, false),
+		gitlabProject(5, 10, false),
+	}
+
+	tests := []struct {
+		name       string
+		linkOnly   bool
+		query      app.ProjectsQuery
+		count      int
+		wantIDs    []int
+		wantParams url.Values
+		wantPages  int
+		wantErr    bool
+	}{
+		{
+			name:    "empty language",
+			query:   app.ProjectsQuery{},
+			count:   1,
+			wantErr: true,
+		},
+		{
+			name:    "invalid count",
+			query:   app.ProjectsQuery{Language: "go"},
+			count:   1001,
+			wantErr: true,
+		},
+		{
+			name: "license filter",
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter:   app.ProjectFilter{License: "mit"},
+			},
+			count:   1,
+			wantErr: true,
+		},
+		{
+			name:    "single page",
+			query:   app.ProjectsQuery{Language: "Go"},
+			count:   2,
+			wantIDs: []int{1, 2},
+			wantParams: url.Values{
+				"with_programming_language": {"Go"},
+				"order_by":                  {"star_count"},
+				"sort":                      {"desc"},
+				"per_page":                  {"2"},
+			},
+			wantPages: 1,
+		},
+		{
+			name:      "pages from x-next-page header",
+			query:     app.ProjectsQuery{Language: "go"},
+			count:     10,
+			wantIDs:   []int{1, 2, 3, 4, 5},
+			wantPages: 3,
+		},
+		{
+			name:      "pages from link header",
+			linkOnly:  true,
+			query:     app.ProjectsQuery{Language: "go"},
+			count:     10,
+			wantIDs:   []int{1, 2, 3, 4, 5},
+			wantPages: 3,
+		},
+		{
+			name: "server side filters",
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter: app.ProjectFilter{
+					ExcludeArchived: true,
+					Topics:          []string{"cli", "tools"},
+					PushedAfter:     time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			count:   1,
+			wantIDs: []int{1},
+			wantParams: url.Values{
+				"with_programming_language": {"go"},
+				"order_by":                  {"star_count"},
+				"sort":                      {"desc"},
+				"per_page":                  {"1"},
+				"archived":                  {"false"},
+				"topic":                     {"cli,tools"},
+				"last_activity_after":       {"2020-01-02T00:00:00Z"},
+			},
+			wantPages: 1,
+		},
+		{
+			name: "forks excluded",
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter:   app.ProjectFilter{ExcludeForks: true},
+			},
+			count:     3,
+			wantIDs:   []int{1, 3, 4},
+			wantPages: 2,
+		},
+		{
+			name: "min stars stops paging",
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter:   app.ProjectFilter{MinStars: 30},
+			},
+			count:     5,
+			wantIDs:   []int{1, 2, 3},
+			wantPages: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeGitlab{
+				projects: projects,
+				linkOnly: tt.linkOnly,
+			}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			c := NewClient(http.DefaultClient, server.URL+"/api/v4/", "token")
+
+			got, err := c.ProjectsByLanguage(context.Background(), tt.query, tt.count)
+			if tt.wantErr {
+				assert.True(t, app.IsInvalidRequestError(err))
+				assert.Empty(t, fake.requests)
+				return
+			}
+			require.NoError(t, err)
+
+			var ids []int
+			for _, p := range got {
+				ids = append(ids, p.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+
+			require.Len(t, fake.requests, tt.wantPages)
+			for _, r := range fake.requests {
+				assert.Equal(t, "token", r.Header.Get("PRIVATE-TOKEN"))
+				assert.Equal(t, "/api/v4/projects", r.URL.Path)
+			}
+			if tt.wantParams != nil {
+				assert.Equal(t, tt.wantParams, fake.requests[0].URL.Query())
+			}
+		})
+	}
+}
+
+func TestClient_ProjectsByLanguageMapping(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(&fakeGitlab{
+		projects: []string{gitlabProject(1, 50, false)},
+	})
+	defer server.Close()
+	c := NewClient(http.DefaultClient, server.URL+"/api/v4", "")
+
+	projects, err := c.ProjectsByLanguage(context.Background(), app.ProjectsQuery{Language: "go"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []app.Project{
+		{
+			ID:            1,
+			Name:          "project1",
+			OwnerLogin:    "group/subgroup",
+			Stars:         50,
+			Forks:         1,
+			Description:   "Project 1",
+			URL:           "https://gitlab.com/group/subgroup/project1",
+			DefaultBranch: "main",
+			PushedAt:      time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC),
+		},
+	}, projects)
+}
+
+func TestClient_StatsByProject(t *testing.T) {
+	t.Parallel()
+
+	contributors := []string{
+		`{"name":"Jane Doe","email":"jane@example.com","commits":10,"additions":100,"deletions":10}`,
+		`{"name":"John Doe","email":"john@example.com","commits":5,"additions":0,"deletions":0}`,
+		`{"name":"jane","email":"JANE@example.com","commits":2,"additions":20,"deletions":2}`,
+	}
+	wantStats := []app.ContributorStats{
+		{
+			Contributor: app.Contributor{
+				ID:    forge.NewEmailIDs().ID("jane@example.com"),
+				Login: "Jane Doe",
+			},
+			Commits:   12,
+			Additions: 120,
+			Deletions: 12,
+		},
+		{
+			Contributor: app.Contributor{
+				ID:    forge.NewEmailIDs().ID("john@example.com"),
+				Login: "John Doe",
+			},
+			Commits: 5,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		linkOnly       bool
+		project        string
+		owner          string
+		want           []app.ContributorStats
+		wantPages      int
+		wantInvalid    bool
+		wantNotFound   bool
+		wantRetryAfter time.Duration
+		wantErr        bool
+	}{
+		{
+			name:        "empty name",
+			owner:       "group",
+			wantInvalid: true,
+			wantErr:     true,
+		},
+		{
+			name:        "empty owner",
+			project:     "project",
+			wantInvalid: true,
+			wantErr:     true,
+		},
+		{
+			name:      "pages from x-next-page header, same author merged",
+			project:   "project",
+			owner:     "group/subgroup",
+			want:      wantStats,
+			wantPages: 2,
+		},
+		{
+			name:      "pages from link header",
+			linkOnly:  true,
+			project:   "project",
+			owner:     "group/subgroup",
+			want:      wantStats,
+			wantPages: 2,
+		},
+		{
+			name:         "not found",
+			project:      "missing",
+			owner:        "group",
+			wantPages:    1,
+			wantNotFound: true,
+			wantErr:      true,
+		},
+		{
+			name:           "rate limited",
+			project:        "limited",
+			owner:          "group",
+			wantPages:      1,
+			wantRetryAfter: 30 * time.Second,
+			wantErr:        true,
+		},
+		{
+			name:      "server error",
+			project:   "broken",
+			owner:     "group",
+			wantPages: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeGitlab{
+				contributors: contributors,
+				linkOnly:     tt.linkOnly,
+			}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			c := NewClient(http.DefaultClient, server.URL+"/api/v4", "")
+
+			got, err := c.StatsByProject(context.Background(), tt.project, tt.owner)
+			require.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantInvalid, app.IsInvalidRequestError(err))
+			assert.Equal(t, tt.wantNotFound, app.IsNotFoundError(err))
+
+			var rateLimitErr app.RateLimitError
+			assert.Equal(t, tt.wantRetryAfter != 0, errors.As(err, &rateLimitErr))
+			assert.Equal(t, tt.wantRetryAfter, rateLimitErr.RetryAfter)
+
+			require.Len(t, fake.requests, tt.wantPages)
+			for _, r := range fake.requests {
+				assert.Empty(t, r.Header.Get("PRIVATE-TOKEN"))
+				assert.Equal(t, "commits", r.URL.Query().Get("order_by"))
+			}
+		})
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   string
+	}{
+		{
+			name:   "no headers",
+			header: http.Header{},
+			want:   "",
+		},
+		{
+			name: "last page",
+			header: http.Header{
+				"X-Next-Page": {""},
+			},
+			want: "",
+		},
+		{
+			name: "x-next-page preferred over link",
+			header: http.Header{
+				"X-Next-Page": {"3"},
+				"Link":        {`<https://gitlab.example.com/api/v4/projects?page=3&per_page=2>; rel="next"`},
+			},
+			want: "http://gitlab.internal/api/v4/projects?page=3&per_page=2",
+		},
+		{
+			name: "link only",
+			header: http.Header{
+				"Link": {`<https://gitlab.example.com/api/v4/projects?page=3&per_page=2>; rel="next"`},
+			},
+			want: "https://gitlab.example.com/api/v4/projects?page=3&per_page=2",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := nextPageURL("http://gitlab.internal/api/v4/projects?page=2&per_page=2", tt.header)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package gitlab
+
+import (
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+type projectsResponse []projectsResponseItem
+
+type projectsResponseItem struct {
+	ID             int                         `json:"id"`
+	Path           string                      `json:"path"`
+	Namespace      projectsResponseItemNS      `json:"namespace"`
+	Stars          int                         `json:"star_count"`
+	Forks          int                         `json:"forks_count"`
+	Description    string                      `json:"description"`
+	WebURL         string                      `json:"web_url"`
+	DefaultBranch  string                      `json:"default_branch"`
+	LastActivityAt time.Time                   `json:"last_activity_at"`
+	ForkedFrom     *projectsResponseItemParent `json:"forked_from_project"`
+}
+
+type projectsResponseItemNS struct {
+	FullPath string `json:"full_path"`
+}
+
+type projectsResponseItemParent struct {
+	ID int `json:"id"`
+}
+
+// ToProject converts gitlab project to app's project. Project's name is its path, and owner is the full path
+// of its namespace, so they identify the project in the api.
+func (i projectsResponseItem) ToProject() app.Project {
+	return app.Project{
+		ID:            i.ID,
+		Name:          i.Path,
+		OwnerLogin:    i.Namespace.FullPath,
+		Stars:         i.Stars,
+		Forks:         i.Forks,
+		Description:   i.Description,
+		URL:           i.WebURL,
+		DefaultBranch: i.DefaultBranch,
+		PushedAt:      i.LastActivityAt.UTC(),
+	}
+}
+
+type contributorsResponse []struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Commits   int    `json:"commits"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// ToStats converts contributors to stats. Contributors with the same email are merged.
+func (s contributorsResponse) ToStats(ids *forge.EmailIDs) []app.ContributorStats {
+	ss := make([]app.ContributorStats, 0, len(s))
+	idx := make(map[int]int, len(s))
+	for _, el := range s {
+		id := ids.ID(el.Email)
+		i, ok := idx[id]
+		if !ok {
+			i = len(ss)
+			idx[id] = i
+			ss = append(ss, app.ContributorStats{
+				Contributor: app.Contributor{
+					ID:    id,
+					Login: el.Name,
+				},
+			})
+		}
+		ss[i].Commits += el.Commits
+		ss[i].Additions += el.Additions
+		ss[i].Deletions += el.Deletions
+	}
+
+	return ss
+}
@@ -0,0 +1,83 @@
+// Package identity provides storage for contributors' aliases.
+package identity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+// aliasesKey is kv store key under which aliases are saved.
+const aliasesKey = "al/aliases"
+
+// KVStore provides simple kv data storage
+type KVStore interface {
+	ReadKey(key []byte) ([]byte, error)
+	UpdateKey(key []byte, data []byte) error
+}
+
+// Store persists aliases managed at runtime in kv store.
+// This struct is an adapter for app.AliasStore.
+type Store struct {
+	store KVStore
+}
+
+var _ app.AliasStore = &Store{}
+
+// NewStore creates new Store instance.
+func NewStore(store KVStore) *Store {
+	return &Store{
+		store: store,
+	}
+}
+
+// Aliases returns stored aliases, mapping alias login to login of the main identity.
+func (s *Store) Aliases() (map[string]string, error) {
+	data, err := s.store.ReadKey([]byte(aliasesKey))
+	if err != nil {
+		return nil, fmt.Errorf("reading aliases: %w", err)
+	}
+	if data == nil {
+		return map[string]string{}, nil
+	}
+
+	var aliases map[string]string
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("unmarshalling json: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// SaveAliases replaces stored aliases with given ones.
+func (s *Store) SaveAliases(aliases map[string]string) error {
+	data, err := json.Marshal(aliases)
+	if err != nil {
+		return fmt.Errorf("marshalling json: %w", err)
+	}
+	if err := s.store.UpdateKey([]byte(aliasesKey), data); err != nil {
+		return fmt.Errorf("saving aliases: %w", err)
+	}
+
+	return nil
+}
+
+// LoadMappingFile reads aliases mapping from json file.
+// File contains object mapping login of the main identity to list of its aliases, for example:
+//
+//	{"octocat": ["octocat-work", "octocat-old"]}
+func LoadMappingFile(path string) (map[string][]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading mapping file: %w", err)
+	}
+
+	var mapping map[string][]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("parsing mapping file: %w", err)
+	}
+
+	return mapping, nil
+}
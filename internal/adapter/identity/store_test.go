@@ -0,0 +1,56 @@
+package identity
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	kv := mock.NewKVStore(nil, nil)
+	s := NewStore(kv)
+
+	aliases, err := s.Aliases()
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+
+	want := map[string]string{
+		"a-work": "a",
+		"b-work": "b",
+	}
+	require.NoError(t, s.SaveAliases(want))
+
+	aliases, err = s.Aliases()
+	require.NoError(t, err)
+	assert.Equal(t, want, aliases)
+}
+
+func TestLoadMappingFile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "aliases")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	validPath := filepath.Join(dir, "valid.json")
+	require.NoError(t, ioutil.WriteFile(validPath, []byte(`{"a": ["a-work", "a-old"]}`), 0600))
+	invalidPath := filepath.Join(dir, "invalid.json")
+	require.NoError(t, ioutil.WriteFile(invalidPath, []byte(`["a"]`), 0600))
+
+	mapping, err := LoadMappingFile(validPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"a": {"a-work", "a-old"}}, mapping)
+
+	_, err = LoadMappingFile(invalidPath)
+	assert.Error(t, err)
+
+	_, err = LoadMappingFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+// Package snapshot provides storage for leaderboard snapshots.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+)
+
+// KVStore provides simple kv data storage
+type KVStore interface {
+	ReadKey(key []byte) ([]byte, error)
+	UpdateKey(key []byte, data []byte) error
+}
+
+// Store persists snapshots in kv store.
+// Each snapshot is saved under separate key, list of snapshot times for each language is kept under index key.
+// This struct is an adapter for app.SnapshotStore.
+type Store struct {
+	store KVStore
+
+	// Mutex guarding index updates.
+	m sync.Mutex
+}
+
+var _ app.SnapshotStore = &Store{}
+
+// NewStore creates new Store instance.
+func NewStore(store KVStore) *Store {
+	return &Store{
+		store: store,
+	}
+}
+
+// SaveSnapshot stores snapshot and adds it to language's index.
+// Snapshot's time is stored with second precision, saving snapshot with already existing time overwrites it.
+func (s *Store) SaveSnapshot(snapshot app.Snapshot) error {
+	ts := snapshot.Time.Unix()
+	data, err := json.Marshal(dbEntry{
+		Time:  ts,
+		Stats: snapshot.Stats,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling json: %w", err)
+	}
+	if err := s.store.UpdateKey(s.snapshotKey(snapshot.Language, ts), data); err != nil {
+		return fmt.Errorf("saving snapshot: %w", err)
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	index, err := s.readIndex(snapshot.Language)
+	if err != nil {
+		return err
+	}
+	i := sort.Search(len(index), func(i int) bool {
+		return index[i] >= ts
+	})
+	if i < len(index) && index[i] == ts {
+		return nil
+	}
+	index = append(index, 0)
+	copy(index[i+1:], index[i:])
+	index[i] = ts
+
+	data, err = json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("marshalling json: %w", err)
+	}
+	if err := s.store.UpdateKey(s.indexKey(snapshot.Language), data); err != nil {
+		return fmt.Errorf("saving snapshots index: %w", err)
+	}
+
+	return nil
+}
+
+// SnapshotTimes returns times of snapshots stored for given language, in ascending order.
+func (s *Store) SnapshotTimes(language string) ([]time.Time, error) {
+	s.m.Lock()
+	index, err := s.readIndex(language)
+	s.m.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]time.Time, 0, len(index))
+	for _, ts := range index {
+		times = append(times, time.Unix(ts, 0).UTC())
+	}
+
+	return times, nil
+}
+
+// Snapshot returns snapshot taken at given time. Returns nil if there's no such snapshot.
+func (s *Store) Snapshot(language string, t time.Time) (*app.Snapshot, error) {
+	data, err := s.store.ReadKey(s.snapshotKey(language, t.Unix()))
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var entry dbEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshalling json: %w", err)
+	}
+
+	return &app.Snapshot{
+		Language: language,
+		Time:     time.Unix(entry.Time, 0).UTC(),
+		Stats:    entry.Stats,
+	}, nil
+}
+
+func (s *Store) readIndex(language string) ([]int64, error) {
+	data, err := s.store.ReadKey(s.indexKey(language))
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshots index: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var index []int64
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("unmarshalling json: %w", err)
+	}
+
+	return index, nil
+}
+
+// indexKey returns key of language's snapshots index. Index and snapshot keys have separate prefixes,
+// as language can contain "/" and its index key could be a snapshot key of other language otherwise.
+func (s *Store) indexKey(language string) []byte {
+	return []byte("sni/" + language)
+}
+
+func (s *Store) snapshotKey(language string, ts int64) []byte {
+	return []byte("sns/" + language + "/" + strconv.FormatInt(ts, 10))
+}
+
+type dbEntry struct {
+	Time  int64
+	Stats []app.ContributorStats
+}
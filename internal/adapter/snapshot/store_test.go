@@ -0,0 +1,101 @@
+package snapshot
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge/mock"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	kv := mock.NewKVStore(nil, nil)
+	s := NewStore(kv)
+
+	times, err := s.SnapshotTimes("go")
+	require.NoError(t, err)
+	assert.Empty(t, times)
+
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	snapshot := func(language string, t time.Time, commits int) app.Snapshot {
+		return app.Snapshot{
+			Language: language,
+			Time:     t,
+			Stats: []app.ContributorStats{
+				{
+					Contributor: app.Contributor{
+						ID:    1,
+						Login: "c",
+					},
+					Commits: commits,
+				},
+			},
+		}
+	}
+
+	// Saved out of order, with duplicate and other language snapshot.
+	require.NoError(t, s.SaveSnapshot(snapshot("go", t2, 2)))
+	require.NoError(t, s.SaveSnapshot(snapshot("go", t1, 1)))
+	require.NoError(t, s.SaveSnapshot(snapshot("go", t2, 3)))
+	require.NoError(t, s.SaveSnapshot(snapshot("rust", t1, 4)))
+
+	times, err = s.SnapshotTimes("go")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{t1, t2}, times)
+
+	times, err = s.SnapshotTimes("rust")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{t1}, times)
+
+	got, err := s.Snapshot("go", t2)
+	require.NoError(t, err)
+	want := snapshot("go", t2, 3)
+	assert.Equal(t, &want, got)
+
+	got, err = s.Snapshot("go", t2.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Index of language containing "/" doesn't collide with snapshots of other languages.
+	slashed := "go/" + strconv.FormatInt(t1.Unix(), 10)
+	times, err = s.SnapshotTimes(slashed)
+	require.NoError(t, err)
+	assert.Empty(t, times)
+	require.NoError(t, s.SaveSnapshot(snapshot(slashed, t2, 5)))
+	got, err = s.Snapshot("go", t1)
+	require.NoError(t, err)
+	want = snapshot("go", t1, 1)
+	assert.Equal(t, &want, got)
+}
+
+func TestStoreErrors(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore(&failingKVStore{})
+
+	err := s.SaveSnapshot(app.Snapshot{Language: "go", Time: time.Now()})
+	assert.Error(t, err)
+
+	_, err = s.SnapshotTimes("go")
+	assert.Error(t, err)
+
+	_, err = s.Snapshot("go", time.Now())
+	assert.Error(t, err)
+}
+
+type failingKVStore struct{}
+
+func (failingKVStore) ReadKey([]byte) ([]byte, error) {
+	return nil, errors.New("read error")
+}
+
+func (failingKVStore) UpdateKey([]byte, []byte) error {
+	return errors.New("update error")
+}
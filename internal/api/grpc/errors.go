@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfterMetadataKey is the metadata key of error response trailer, holding number of seconds
+// after which failed call can be retried.
+const RetryAfterMetadataKey = "retry-after"
+
+// serviceError converts app service error to grpc status error with matching code.
+// Errors without matching code are returned unchanged.
+func serviceError(ctx context.Context, err error) error {
+	if app.IsNotFoundError(err) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	var rateLimitErr app.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		// Exhausted quota makes service unavailable for everyone until reset.
+		code := codes.ResourceExhausted
+		if rateLimitErr.Exhausted {
+			code = codes.Unavailable
+		}
+		retryAfter := int((rateLimitErr.RetryAfter + time.Second - 1) / time.Second)
+		// Trailer can't be set outside of grpc call context. It's informational, so the error is ignored.
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterMetadataKey, strconv.Itoa(retryAfter)))
+		return status.Error(code, err.Error())
+	}
+
+	return err
+}
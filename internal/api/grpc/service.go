@@ -3,18 +3,34 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/m-zajac/goprojectdemo/internal/app"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
-// AppService can return most active contributors.
+// Metadata keys of MostActiveContributors response headers.
+const (
+	// PartialMetadataKey holds "true" if some projects were skipped, because their stats weren't retrieved in time.
+	PartialMetadataKey = "x-partial"
+	// CompletenessMetadataKey holds share of projects which stats are taken into account, in range <0..1>.
+	CompletenessMetadataKey = "x-completeness"
+)
+
+// AppService can return most active contributors, contributor profiles, top projects and their health metrics.
 type AppService interface {
-	MostActiveContributors(
+	MostActiveContributors(ctx context.Context, q app.ContributorsQuery) (*app.ContributorsResult, error)
+	ContributorProfile(
 		ctx context.Context,
+		forge string,
+		login string,
 		language string,
 		projectsCount int,
-		count int,
-	) ([]app.ContributorStats, error)
+	) (*app.ContributorProfile, error)
+	TopProjects(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error)
+	HealthMetrics(ctx context.Context, forge string, language string, projectsCount int) (*app.HealthMetrics, error)
 }
 
 // Service implements ServiceServer definition, acting as a direct proxy to AppService.
@@ -31,27 +47,212 @@ func NewService(appService AppService) *Service {
 
 // MostActiveContributors calls service and returns reply.
 func (s *Service) MostActiveContributors(ctx context.Context, r *Request) (*Reply, error) {
-	stats, err := s.appService.MostActiveContributors(
-		ctx,
-		r.Language,
-		int(r.ProjectsCount),
-		int(r.Count),
-	)
+	rankingMode, err := app.ParseRankingMode(r.RankBy)
+	if err != nil {
+		return nil, err
+	}
+	window, err := app.NewTimeWindow(unixTime(r.Since), unixTime(r.Until), int(r.Weeks), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	q := app.ContributorsQuery{
+		Forge:         r.Forge,
+		Language:      r.Language,
+		ProjectsCount: int(r.ProjectsCount),
+		Count:         int(r.Count),
+		Ranking: app.Ranking{
+			Mode: rankingMode,
+		},
+		Window:        window,
+		BestEffort:    r.BestEffort,
+		Exclude:       r.Exclude,
+		ProjectFilter: newProjectFilter(r.ProjectFilter),
+	}
+	if w := r.Weights; w != nil {
+		q.Ranking.Weights = app.ScoreWeights{
+			Commits:   w.Commits,
+			Additions: w.Additions,
+			Deletions: w.Deletions,
+		}
+	}
+
+	result, err := s.appService.MostActiveContributors(ctx, q)
 	if err != nil {
-		return nil, fmt.Errorf("service.MostActiveContributors: %w", err)
+		return nil, serviceError(ctx, fmt.Errorf("service.MostActiveContributors: %w", err))
 	}
 
-	replyStats := make([]*Stat, 0, len(stats))
-	for _, st := range stats {
+	// Header can't be set outside of grpc call context. It's informational, so the error is ignored.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		PartialMetadataKey, strconv.FormatBool(result.Partial()),
+		CompletenessMetadataKey, strconv.FormatFloat(result.Completeness, 'f', -1, 64),
+	))
+
+	replyStats := make([]*Stat, 0, len(result.Stats))
+	for _, st := range result.Stats {
 		replyStats = append(replyStats, &Stat{
 			Contributor: &Contributor{
 				Id:    int64(st.Contributor.ID),
 				Login: st.Contributor.Login,
 			},
-			Commits: int32(st.Commits),
+			Commits:   int32(st.Commits),
+			Additions: int64(st.Additions),
+			Deletions: int64(st.Deletions),
+			Projects:  newProjectCommits(st.Projects),
+			Aliases:   newContributors(st.Aliases),
+		})
+	}
+	var replySkipped []*SkippedProject
+	for _, sp := range result.Skipped {
+		replySkipped = append(replySkipped, &SkippedProject{
+			Owner:  sp.Project.OwnerLogin,
+			Name:   sp.Project.Name,
+			Reason: string(sp.Reason),
 		})
 	}
 	return &Reply{
-		Stat: replyStats,
+		Stat:       replyStats,
+		Incomplete: result.Incomplete(),
+		Skipped:    replySkipped,
+		Filtered:   int32(result.Filtered),
+	}, nil
+}
+
+// ContributorProfile calls service and returns reply.
+func (s *Service) ContributorProfile(ctx context.Context, r *ProfileRequest) (*ProfileReply, error) {
+	profile, err := s.appService.ContributorProfile(ctx, r.Forge, r.Login, r.Language, int(r.ProjectsCount))
+	if err != nil {
+		return nil, serviceError(ctx, fmt.Errorf("service.ContributorProfile: %w", err))
+	}
+
+	return &ProfileReply{
+		Contributor: &Contributor{
+			Id:    int64(profile.Contributor.ID),
+			Login: profile.Contributor.Login,
+		},
+		Language:          profile.Language,
+		Rank:              int32(profile.Rank),
+		ContributorsCount: int32(profile.ContributorsCount),
+		Commits:           int32(profile.Commits),
+		Additions:         int64(profile.Additions),
+		Deletions:         int64(profile.Deletions),
+		Projects:          newProjectCommits(profile.Projects),
+		Aliases:           newContributors(profile.Aliases),
+	}, nil
+}
+
+// TopProjects calls service and returns reply.
+func (s *Service) TopProjects(ctx context.Context, r *ProjectsRequest) (*ProjectsReply, error) {
+	q := app.ProjectsQuery{
+		Forge:    r.Forge,
+		Language: r.Language,
+		Filter:   newProjectFilter(r.ProjectFilter),
+	}
+	projects, err := s.appService.TopProjects(ctx, q, int(r.Count))
+	if err != nil {
+		return nil, serviceError(ctx, fmt.Errorf("service.TopProjects: %w", err))
+	}
+
+	replyProjects := make([]*Project, 0, len(projects))
+	for _, p := range projects {
+		var pushedAt int64
+		if !p.PushedAt.IsZero() {
+			pushedAt = p.PushedAt.Unix()
+		}
+		replyProjects = append(replyProjects, &Project{
+			Id:            int64(p.ID),
+			Owner:         p.OwnerLogin,
+			Name:          p.Name,
+			Url:           p.URL,
+			Description:   p.Description,
+			Stars:         int32(p.Stars),
+			Forks:         int32(p.Forks),
+			DefaultBranch: p.DefaultBranch,
+			PushedAt:      pushedAt,
+			License:       p.License,
+		})
+	}
+
+	return &ProjectsReply{
+		Projects: replyProjects,
 	}, nil
 }
+
+// HealthMetrics calls service and returns reply.
+func (s *Service) HealthMetrics(ctx context.Context, r *HealthRequest) (*HealthReply, error) {
+	metrics, err := s.appService.HealthMetrics(ctx, r.Forge, r.Language, int(r.ProjectsCount))
+	if err != nil {
+		return nil, serviceError(ctx, fmt.Errorf("service.HealthMetrics: %w", err))
+	}
+
+	projects := make([]*ProjectHealth, 0, len(metrics.Projects))
+	for _, p := range metrics.Projects {
+		projects = append(projects, &ProjectHealth{
+			Owner:   p.Project.OwnerLogin,
+			Name:    p.Project.Name,
+			Metrics: newConcentrationMetrics(p.Metrics),
+		})
+	}
+
+	return &HealthReply{
+		Overall:  newConcentrationMetrics(metrics.Overall),
+		Projects: projects,
+	}, nil
+}
+
+func newConcentrationMetrics(m app.ConcentrationMetrics) *ConcentrationMetrics {
+	return &ConcentrationMetrics{
+		Contributors: int32(m.Contributors),
+		Commits:      int32(m.Commits),
+		BusFactor:    int32(m.BusFactor),
+		Gini:         m.Gini,
+		Top1Share:    m.Top1Share,
+		Top5Share:    m.Top5Share,
+		Top10Share:   m.Top10Share,
+	}
+}
+
+// newProjectFilter converts optional request's project filter to app's filter.
+func newProjectFilter(f *ProjectFilter) app.ProjectFilter {
+	if f == nil {
+		return app.ProjectFilter{}
+	}
+	return app.ProjectFilter{
+		MinStars:        int(f.MinStars),
+		ExcludeForks:    f.ExcludeForks,
+		ExcludeArchived: f.ExcludeArchived,
+		Topics:          f.Topics,
+		PushedAfter:     unixTime(f.PushedAfter),
+		License:         f.License,
+	}
+}
+
+func newProjectCommits(contributions []app.ProjectContribution) []*ProjectCommits {
+	var projects []*ProjectCommits
+	for _, p := range contributions {
+		projects = append(projects, &ProjectCommits{
+			Owner:   p.Project.OwnerLogin,
+			Name:    p.Project.Name,
+			Commits: int32(p.Commits),
+		})
+	}
+	return projects
+}
+
+func newContributors(contributors []app.Contributor) []*Contributor {
+	var result []*Contributor
+	for _, c := range contributors {
+		result = append(result, &Contributor{
+			Id:    int64(c.ID),
+			Login: c.Login,
+		})
+	}
+	return result
+}
+
+// unixTime converts unix timestamp to time. Zero timestamp means zero time.
+func unixTime(ts int64) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
+}
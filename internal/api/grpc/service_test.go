@@ -3,22 +3,29 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/m-zajac/goprojectdemo/internal/api/http/mock"
 	"github.com/m-zajac/goprojectdemo/internal/app"
 	"github.com/stretchr/testify/require"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestServiceMostActiveContributors(t *testing.T) {
 	tests := []struct {
-		name           string
-		req            *Request
-		appResultStats []app.ContributorStats
-		appResultErr   error
-		want           *Reply
-		wantErr        bool
+		name         string
+		req          *Request
+		wantQuery    *app.ContributorsQuery
+		appResult    *app.ContributorsResult
+		appResultErr error
+		want         *Reply
+		wantErr      bool
 	}{
 		{
 			name: "app service error",
@@ -27,10 +34,18 @@ func TestServiceMostActiveContributors(t *testing.T) {
 				ProjectsCount: 7,
 				Count:         11,
 			},
-			appResultStats: nil,
-			appResultErr:   errors.New("test error"),
-			want:           nil,
-			wantErr:        true,
+			wantQuery: &app.ContributorsQuery{
+				Language:      "x",
+				ProjectsCount: 7,
+				Count:         11,
+				Ranking: app.Ranking{
+					Mode: app.RankByCommits,
+				},
+			},
+			appResult:    nil,
+			appResultErr: errors.New("test error"),
+			want:         nil,
+			wantErr:      true,
 		},
 		{
 			name: "app service ok, valid response",
@@ -38,20 +53,65 @@ func TestServiceMostActiveContributors(t *testing.T) {
 				Language:      "y",
 				ProjectsCount: 13,
 				Count:         2,
+				RankBy:        "score",
+				Weights: &ScoreWeights{
+					Commits:   1,
+					Deletions: 0.5,
+				},
+				Since: 1577836800,
+				ProjectFilter: &ProjectFilter{
+					MinStars:     100,
+					ExcludeForks: true,
+					Topics:       []string{"cli"},
+				},
 			},
-			appResultStats: []app.ContributorStats{
-				{
-					Commits: 1,
-					Contributor: app.Contributor{
-						ID:    1,
-						Login: "l1",
+			wantQuery: &app.ContributorsQuery{
+				Language:      "y",
+				ProjectsCount: 13,
+				Count:         2,
+				ProjectFilter: app.ProjectFilter{
+					MinStars:     100,
+					ExcludeForks: true,
+					Topics:       []string{"cli"},
+				},
+				Ranking: app.Ranking{
+					Mode: app.RankByScore,
+					Weights: app.ScoreWeights{
+						Commits:   1,
+						Deletions: 0.5,
 					},
 				},
-				{
-					Commits: 2,
-					Contributor: app.Contributor{
-						ID:    5,
-						Login: "l2",
+				Window: app.TimeWindow{
+					Since: time.Unix(1577836800, 0),
+				},
+			},
+			appResult: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits:   1,
+						Additions: 10,
+						Deletions: 3,
+						Contributor: app.Contributor{
+							ID:    1,
+							Login: "l1",
+						},
+					},
+					{
+						Commits: 2,
+						Contributor: app.Contributor{
+							ID:    5,
+							Login: "l2",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         3,
+									Name:       "p",
+									OwnerLogin: "o",
+								},
+								Commits: 2,
+							},
+						},
 					},
 				},
 			},
@@ -59,7 +119,9 @@ func TestServiceMostActiveContributors(t *testing.T) {
 			want: &Reply{
 				Stat: []*Stat{
 					{
-						Commits: 1,
+						Commits:   1,
+						Additions: 10,
+						Deletions: 3,
 						Contributor: &Contributor{
 							Id:    int64(1),
 							Login: "l1",
@@ -71,11 +133,73 @@ func TestServiceMostActiveContributors(t *testing.T) {
 							Id:    int64(5),
 							Login: "l2",
 						},
+						Projects: []*ProjectCommits{
+							{
+								Owner:   "o",
+								Name:    "p",
+								Commits: 2,
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "app service ok, incomplete response",
+			req: &Request{
+				Language:   "y",
+				Count:      2,
+				BestEffort: true,
+				Exclude:    []string{"*-ci"},
+			},
+			wantQuery: &app.ContributorsQuery{
+				Language: "y",
+				Count:    2,
+				Ranking: app.Ranking{
+					Mode: app.RankByCommits,
+				},
+				BestEffort: true,
+				Exclude:    []string{"*-ci"},
+			},
+			appResult: &app.ContributorsResult{
+				Filtered: 2,
+				Skipped: []app.SkippedProject{
+					{
+						Project: app.Project{
+							ID:         1,
+							Name:       "p",
+							OwnerLogin: "o",
+						},
+						Reason: app.SkipReasonTimeout,
+					},
+				},
+			},
+			appResultErr: nil,
+			want: &Reply{
+				Stat:       []*Stat{},
+				Incomplete: true,
+				Filtered:   2,
+				Skipped: []*SkippedProject{
+					{
+						Owner:  "o",
+						Name:   "p",
+						Reason: "timeout",
 					},
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid ranking mode",
+			req: &Request{
+				Language: "z",
+				Count:    1,
+				RankBy:   "stars",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -83,9 +207,11 @@ func TestServiceMostActiveContributors(t *testing.T) {
 			defer ctrl.Finish()
 
 			appService := mock.NewMockService(ctrl)
-			appService.EXPECT().
-				MostActiveContributors(gomock.Any(), tt.req.Language, int(tt.req.ProjectsCount), int(tt.req.Count)).
-				Return(tt.appResultStats, tt.appResultErr)
+			if tt.wantQuery != nil {
+				appService.EXPECT().
+					MostActiveContributors(gomock.Any(), *tt.wantQuery).
+					Return(tt.appResult, tt.appResultErr)
+			}
 
 			s := &Service{appService: appService}
 
@@ -95,3 +221,443 @@ func TestServiceMostActiveContributors(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceContributorProfile(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *ProfileRequest
+		appResult    *app.ContributorProfile
+		appResultErr error
+		want         *ProfileReply
+		wantErr      bool
+		wantCode     codes.Code
+	}{
+		{
+			name: "app service error",
+			req: &ProfileRequest{
+				Login:         "l",
+				Language:      "x",
+				ProjectsCount: 7,
+			},
+			appResult:    nil,
+			appResultErr: errors.New("test error"),
+			want:         nil,
+			wantErr:      true,
+			wantCode:     codes.Unknown,
+		},
+		{
+			name: "contributor not found",
+			req: &ProfileRequest{
+				Login:         "l",
+				Language:      "x",
+				ProjectsCount: 7,
+			},
+			appResult:    nil,
+			appResultErr: app.NotFoundError("contributor 'l' has no commits in top x projects"),
+			want:         nil,
+			wantErr:      true,
+			wantCode:     codes.NotFound,
+		},
+		{
+			name: "app service ok, valid response",
+			req: &ProfileRequest{
+				Forge:         app.ForgeGitlab,
+				Login:         "l",
+				Language:      "x",
+				ProjectsCount: 7,
+			},
+			appResult: &app.ContributorProfile{
+				Contributor: app.Contributor{
+					ID:    3,
+					Login: "L",
+				},
+				Language:          "x",
+				Rank:              1,
+				ContributorsCount: 5,
+				Commits:           4,
+				Additions:         40,
+				Deletions:         4,
+				Projects: []app.ProjectContribution{
+					{
+						Project: app.Project{
+							ID:         1,
+							Name:       "p",
+							OwnerLogin: "o",
+						},
+						Commits: 4,
+					},
+				},
+				Aliases: []app.Contributor{
+					{
+						ID:    4,
+						Login: "l-work",
+					},
+				},
+			},
+			appResultErr: nil,
+			want: &ProfileReply{
+				Contributor: &Contributor{
+					Id:    3,
+					Login: "L",
+				},
+				Language:          "x",
+				Rank:              1,
+				ContributorsCount: 5,
+				Commits:           4,
+				Additions:         40,
+				Deletions:         4,
+				Projects: []*ProjectCommits{
+					{
+						Owner:   "o",
+						Name:    "p",
+						Commits: 4,
+					},
+				},
+				Aliases: []*Contributor{
+					{
+						Id:    4,
+						Login: "l-work",
+					},
+				},
+			},
+			wantErr:  false,
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			appService := mock.NewMockService(ctrl)
+			appService.EXPECT().
+				ContributorProfile(gomock.Any(), tt.req.Forge, tt.req.Login, tt.req.Language, int(tt.req.ProjectsCount)).
+				Return(tt.appResult, tt.appResultErr)
+
+			s := &Service{appService: appService}
+
+			got, err := s.ContributorProfile(context.Background(), tt.req)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantCode, status.Code(err))
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServiceTopProjects(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *ProjectsRequest
+		wantQuery    app.ProjectsQuery
+		appResult    []app.Project
+		appResultErr error
+		want         *ProjectsReply
+		wantErr      bool
+	}{
+		{
+			name: "app service error",
+			req: &ProjectsRequest{
+				Language: "x",
+				Count:    7,
+			},
+			wantQuery:    app.ProjectsQuery{Language: "x"},
+			appResult:    nil,
+			appResultErr: errors.New("test error"),
+			want:         nil,
+			wantErr:      true,
+		},
+		{
+			name: "app service ok, valid response",
+			req: &ProjectsRequest{
+				Forge:    app.ForgeGitlab,
+				Language: "x",
+				Count:    7,
+				ProjectFilter: &ProjectFilter{
+					ExcludeArchived: true,
+				},
+			},
+			wantQuery: app.ProjectsQuery{
+				Forge:    app.ForgeGitlab,
+				Language: "x",
+				Filter: app.ProjectFilter{
+					ExcludeArchived: true,
+				},
+			},
+			appResult: []app.Project{
+				{
+					ID:            1,
+					Name:          "p",
+					OwnerLogin:    "o",
+					Stars:         100,
+					Forks:         10,
+					Description:   "d",
+					URL:           "https://github.com/o/p",
+					DefaultBranch: "main",
+					PushedAt:      time.Unix(1577836800, 0),
+					License:       "MIT",
+				},
+				{
+					ID:         2,
+					Name:       "p2",
+					OwnerLogin: "o",
+				},
+			},
+			appResultErr: nil,
+			want: &ProjectsReply{
+				Projects: []*Project{
+					{
+						Id:            1,
+						Owner:         "o",
+						Name:          "p",
+						Url:           "https://github.com/o/p",
+						Description:   "d",
+						Stars:         100,
+						Forks:         10,
+						DefaultBranch: "main",
+						PushedAt:      1577836800,
+						License:       "MIT",
+					},
+					{
+						Id:    2,
+						Owner: "o",
+						Name:  "p2",
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			appService := mock.NewMockService(ctrl)
+			appService.EXPECT().
+				TopProjects(gomock.Any(), tt.wantQuery, int(tt.req.Count)).
+				Return(tt.appResult, tt.appResultErr)
+
+			s := &Service{appService: appService}
+
+			got, err := s.TopProjects(context.Background(), tt.req)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServiceHealthMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *HealthRequest
+		appResult    *app.HealthMetrics
+		appResultErr error
+		want         *HealthReply
+		wantErr      bool
+	}{
+		{
+			name: "app service error",
+			req: &HealthRequest{
+				Language:      "x",
+				ProjectsCount: 7,
+			},
+			appResult:    nil,
+			appResultErr: errors.New("test error"),
+			want:         nil,
+			wantErr:      true,
+		},
+		{
+			name: "app service ok, valid response",
+			req: &HealthRequest{
+				Language:      "x",
+				ProjectsCount: 7,
+			},
+			appResult: &app.HealthMetrics{
+				Language: "x",
+				Overall: app.ConcentrationMetrics{
+					Contributors: 2,
+					Commits:      4,
+					BusFactor:    1,
+					Gini:         0.25,
+					Top1Share:    0.75,
+					Top5Share:    1,
+					Top10Share:   1,
+				},
+				Projects: []app.ProjectHealth{
+					{
+						Project: app.Project{
+							ID:         1,
+							Name:       "p",
+							OwnerLogin: "o",
+						},
+						Metrics: app.ConcentrationMetrics{
+							Contributors: 1,
+							Commits:      3,
+							BusFactor:    1,
+							Top1Share:    1,
+							Top5Share:    1,
+							Top10Share:   1,
+						},
+					},
+				},
+			},
+			appResultErr: nil,
+			want: &HealthReply{
+				Overall: &ConcentrationMetrics{
+					Contributors: 2,
+					Commits:      4,
+					BusFactor:    1,
+					Gini:         0.25,
+					Top1Share:    0.75,
+					Top5Share:    1,
+					Top10Share:   1,
+				},
+				Projects: []*ProjectHealth{
+					{
+						Owner: "o",
+						Name:  "p",
+						Metrics: &ConcentrationMetrics{
+							Contributors: 1,
+							Commits:      3,
+							BusFactor:    1,
+							Top1Share:    1,
+							Top5Share:    1,
+							Top10Share:   1,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			appService := mock.NewMockService(ctrl)
+			appService.EXPECT().
+				HealthMetrics(gomock.Any(), tt.req.Forge, tt.req.Language, int(tt.req.ProjectsCount)).
+				Return(tt.appResult, tt.appResultErr)
+
+			s := &Service{appService: appService}
+
+			got, err := s.HealthMetrics(context.Background(), tt.req)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServiceMostActiveContributorsMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	appService := mock.NewMockService(ctrl)
+	appService.EXPECT().
+		MostActiveContributors(gomock.Any(), gomock.Any()).
+		Return(
+			&app.ContributorsResult{
+				Skipped: []app.SkippedProject{
+					{Reason: app.SkipReasonTimeout},
+				},
+				Completeness: 0.75,
+			},
+			nil,
+		)
+
+	s := &Service{appService: appService}
+
+	stream := &testServerTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	_, err := s.MostActiveContributors(ctx, &Request{Language: "go", Count: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"true"}, stream.header.Get(PartialMetadataKey))
+	require.Equal(t, []string{"0.75"}, stream.header.Get(CompletenessMetadataKey))
+}
+
+func TestServiceRateLimitError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            app.RateLimitError
+		wantCode       codes.Code
+		wantRetryAfter string
+	}{
+		{
+			name: "quota exhausted",
+			err: app.RateLimitError{
+				RetryAfter: 1500 * time.Millisecond,
+				Exhausted:  true,
+			},
+			wantCode:       codes.Unavailable,
+			wantRetryAfter: "2",
+		},
+		{
+			name: "rate exceeded",
+			err: app.RateLimitError{
+				RetryAfter: time.Minute,
+			},
+			wantCode:       codes.ResourceExhausted,
+			wantRetryAfter: "60",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			appService := mock.NewMockService(ctrl)
+			appService.EXPECT().
+				TopProjects(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(nil, fmt.Errorf("wrapped: %w", tt.err))
+
+			s := &Service{appService: appService}
+
+			stream := &testServerTransportStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+			_, err := s.TopProjects(ctx, &ProjectsRequest{Language: "go", Count: 1})
+			require.Error(t, err)
+			require.Equal(t, tt.wantCode, status.Code(err))
+			require.Equal(t, []string{tt.wantRetryAfter}, stream.trailer.Get(RetryAfterMetadataKey))
+		})
+	}
+}
+
+func TestServiceNotFoundError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	appService := mock.NewMockService(ctrl)
+	appService.EXPECT().
+		TopProjects(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("wrapped: %w", app.NotFoundError("not found")))
+
+	s := &Service{appService: appService}
+
+	_, err := s.TopProjects(context.Background(), &ProjectsRequest{Language: "nolang", Count: 1})
+	require.Error(t, err)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+type testServerTransportStream struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *testServerTransportStream) Method() string {
+	return "test"
+}
+
+func (s *testServerTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *testServerTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *testServerTransportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
@@ -2,8 +2,15 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/m-zajac/goprojectdemo/internal/app"
 	"github.com/sirupsen/logrus"
@@ -15,31 +22,75 @@ const (
 )
 
 type contributor struct {
-	Name    string `json:"name"`
+	Name      string                `json:"name"`
+	Commits   int                   `json:"commits"`
+	Additions int                   `json:"additions"`
+	Deletions int                   `json:"deletions"`
+	Projects  []projectContribution `json:"projects,omitempty"`
+	Aliases   []string              `json:"aliases,omitempty"`
+}
+
+type projectContribution struct {
+	Project string `json:"project"`
 	Commits int    `json:"commits"`
 }
 
+type skippedProject struct {
+	Project string `json:"project"`
+	Reason  string `json:"reason"`
+}
+
 type contributorsResponse struct {
-	Language     string        `json:"language"`
-	Contributors []contributor `json:"contributors"`
+	Language     string           `json:"language"`
+	Contributors []contributor    `json:"contributors"`
+	Incomplete   bool             `json:"incomplete"`
+	Partial      bool             `json:"partial"`
+	Completeness float64          `json:"completeness"`
+	Skipped      []skippedProject `json:"skipped,omitempty"`
+	Filtered     int              `json:"filtered"`
 }
 
-func newContributorsResponse(language string, contributions []app.ContributorStats) contributorsResponse {
-	contributors := make([]contributor, 0, len(contributions))
-	for _, c := range contributions {
+func newContributorsResponse(language string, result *app.ContributorsResult) contributorsResponse {
+	contributors := make([]contributor, 0, len(result.Stats))
+	for _, c := range result.Stats {
+		var projects []projectContribution
+		for _, p := range c.Projects {
+			projects = append(projects, projectContribution{
+				Project: p.Project.OwnerLogin + "/" + p.Project.Name,
+				Commits: p.Commits,
+			})
+		}
 		contributors = append(contributors, contributor{
-			Name:    c.Contributor.Login,
-			Commits: c.Commits,
+			Name:      c.Contributor.Login,
+			Commits:   c.Commits,
+			Additions: c.Additions,
+			Deletions: c.Deletions,
+			Projects:  projects,
+			Aliases:   aliasLogins(c.Aliases),
+		})
+	}
+
+	var skipped []skippedProject
+	for _, s := range result.Skipped {
+		skipped = append(skipped, skippedProject{
+			Project: s.Project.OwnerLogin + "/" + s.Project.Name,
+			Reason:  string(s.Reason),
 		})
 	}
 
 	return contributorsResponse{
 		Language:     language,
 		Contributors: contributors,
+		Incomplete:   result.Incomplete(),
+		Partial:      result.Partial(),
+		Completeness: result.Completeness,
+		Skipped:      skipped,
+		Filtered:     result.Filtered,
 	}
 }
 
 // NewContributorsHandler creates handlerfunc returning contributions response.
+// Logins matching `exclude` param patterns are excluded even if they're on service's allow list.
 func NewContributorsHandler(
 	getLanguage func(*http.Request) string,
 	service Service,
@@ -47,43 +98,549 @@ func NewContributorsHandler(
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		lang := getLanguage(r)
-		count := getIntParam(r, "count", defaultHandlerCountValue)
+		rankingMode, err := app.ParseRankingMode(r.URL.Query().Get("rankBy"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		window, err := getTimeWindow(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		projectFilter, err := getProjectFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		weights, err := getScoreWeights(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		q := app.ContributorsQuery{
+			Forge:         r.URL.Query().Get("forge"),
+			Language:      lang,
+			ProjectsCount: getIntParam(r, "projectsCount", defaultHandlerProjectsCountValue),
+			ProjectFilter: projectFilter,
+			Count:         getIntParam(r, "count", defaultHandlerCountValue),
+			Ranking: app.Ranking{
+				Mode:    rankingMode,
+				Weights: weights,
+			},
+			Window:     window,
+			BestEffort: getBoolParam(r, "bestEffort"),
+			Exclude:    getListParam(r, "exclude"),
+		}
+
+		result, err := service.MostActiveContributors(r.Context(), q)
+		if err != nil {
+			writeServiceError(w, err, l)
+			return
+		}
+
+		writeJSON(w, newContributorsResponse(lang, result))
+	}
+}
+
+type contributorProfileResponse struct {
+	Login             string                `json:"login"`
+	Language          string                `json:"language"`
+	Rank              int                   `json:"rank"`
+	ContributorsCount int                   `json:"contributorsCount"`
+	Commits           int                   `json:"commits"`
+	Additions         int                   `json:"additions"`
+	Deletions         int                   `json:"deletions"`
+	Projects          []projectContribution `json:"projects"`
+	Aliases           []string              `json:"aliases,omitempty"`
+}
+
+func newContributorProfileResponse(profile *app.ContributorProfile) contributorProfileResponse {
+	projects := make([]projectContribution, 0, len(profile.Projects))
+	for _, p := range profile.Projects {
+		projects = append(projects, projectContribution{
+			Project: p.Project.OwnerLogin + "/" + p.Project.Name,
+			Commits: p.Commits,
+		})
+	}
+
+	return contributorProfileResponse{
+		Login:             profile.Contributor.Login,
+		Language:          profile.Language,
+		Rank:              profile.Rank,
+		ContributorsCount: profile.ContributorsCount,
+		Commits:           profile.Commits,
+		Additions:         profile.Additions,
+		Deletions:         profile.Deletions,
+		Projects:          projects,
+		Aliases:           aliasLogins(profile.Aliases),
+	}
+}
+
+// NewContributorProfileHandler creates handlerfunc returning contributor's profile response.
+func NewContributorProfileHandler(
+	getLogin func(*http.Request) string,
+	service Service,
+	l logrus.FieldLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		login := getLogin(r)
+		forge := r.URL.Query().Get("forge")
+		language := r.URL.Query().Get("language")
 		projectsCount := getIntParam(r, "projectsCount", defaultHandlerProjectsCountValue)
 
-		contributions, err := service.MostActiveContributors(r.Context(), lang, projectsCount, count)
+		profile, err := service.ContributorProfile(r.Context(), forge, login, language, projectsCount)
+		if err != nil {
+			writeServiceError(w, err, l)
+			return
+		}
+
+		writeJSON(w, newContributorProfileResponse(profile))
+	}
+}
+
+type project struct {
+	Name          string    `json:"name"`
+	URL           string    `json:"url"`
+	Description   string    `json:"description"`
+	Stars         int       `json:"stars"`
+	Forks         int       `json:"forks"`
+	DefaultBranch string    `json:"defaultBranch"`
+	PushedAt      time.Time `json:"pushedAt"`
+	License       string    `json:"license"`
+}
+
+type projectsResponse struct {
+	Language string    `json:"language"`
+	Projects []project `json:"projects"`
+}
+
+func newProjectsResponse(language string, projects []app.Project) projectsResponse {
+	resp := projectsResponse{
+		Language: language,
+		Projects: make([]project, 0, len(projects)),
+	}
+	for _, p := range projects {
+		resp.Projects = append(resp.Projects, project{
+			Name:          p.OwnerLogin + "/" + p.Name,
+			URL:           p.URL,
+			Description:   p.Description,
+			Stars:         p.Stars,
+			Forks:         p.Forks,
+			DefaultBranch: p.DefaultBranch,
+			PushedAt:      p.PushedAt,
+			License:       p.License,
+		})
+	}
+
+	return resp
+}
+
+// NewProjectsHandler creates handlerfunc returning top projects response.
+// Returned projects are the ones contributors leaderboard for the same params is computed from.
+func NewProjectsHandler(
+	getLanguage func(*http.Request) string,
+	service Service,
+	l logrus.FieldLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lang := getLanguage(r)
+		projectFilter, err := getProjectFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		q := app.ProjectsQuery{
+			Forge:    r.URL.Query().Get("forge"),
+			Language: lang,
+			Filter:   projectFilter,
+		}
+		count := getIntParam(r, "count", defaultHandlerProjectsCountValue)
+
+		projects, err := service.TopProjects(r.Context(), q, count)
 		if err != nil {
-			if app.IsInvalidRequestError(err) {
-				http.Error(w, err.Error(), http.StatusBadRequest)
+			writeServiceError(w, err, l)
+			return
+		}
+
+		writeJSON(w, newProjectsResponse(lang, projects))
+	}
+}
+
+type concentrationMetrics struct {
+	Contributors int     `json:"contributors"`
+	Commits      int     `json:"commits"`
+	BusFactor    int     `json:"busFactor"`
+	Gini         float64 `json:"gini"`
+	Top1Share    float64 `json:"top1Share"`
+	Top5Share    float64 `json:"top5Share"`
+	Top10Share   float64 `json:"top10Share"`
+}
+
+func newConcentrationMetrics(m app.ConcentrationMetrics) concentrationMetrics {
+	return concentrationMetrics{
+		Contributors: m.Contributors,
+		Commits:      m.Commits,
+		BusFactor:    m.BusFactor,
+		Gini:         m.Gini,
+		Top1Share:    m.Top1Share,
+		Top5Share:    m.Top5Share,
+		Top10Share:   m.Top10Share,
+	}
+}
+
+type projectHealth struct {
+	Project string `json:"project"`
+	concentrationMetrics
+}
+
+type healthMetricsResponse struct {
+	Language string               `json:"language"`
+	Overall  concentrationMetrics `json:"overall"`
+	Projects []projectHealth      `json:"projects"`
+}
+
+func newHealthMetricsResponse(metrics *app.HealthMetrics) healthMetricsResponse {
+	projects := make([]projectHealth, 0, len(metrics.Projects))
+	for _, p := range metrics.Projects {
+		projects = append(projects, projectHealth{
+			Project:              p.Project.OwnerLogin + "/" + p.Project.Name,
+			concentrationMetrics: newConcentrationMetrics(p.Metrics),
+		})
+	}
+
+	return healthMetricsResponse{
+		Language: metrics.Language,
+		Overall:  newConcentrationMetrics(metrics.Overall),
+		Projects: projects,
+	}
+}
+
+// NewHealthMetricsHandler creates handlerfunc returning contribution concentration metrics response.
+func NewHealthMetricsHandler(
+	getLanguage func(*http.Request) string,
+	service Service,
+	l logrus.FieldLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lang := getLanguage(r)
+		forge := r.URL.Query().Get("forge")
+		projectsCount := getIntParam(r, "projectsCount", defaultHandlerProjectsCountValue)
+
+		metrics, err := service.HealthMetrics(r.Context(), forge, lang, projectsCount)
+		if err != nil {
+			writeServiceError(w, err, l)
+			return
+		}
+
+		writeJSON(w, newHealthMetricsResponse(metrics))
+	}
+}
+
+type rankChange struct {
+	Name            string `json:"name"`
+	Rank            int    `json:"rank"`
+	PreviousRank    int    `json:"previousRank"`
+	Climbed         int    `json:"climbed"`
+	Commits         int    `json:"commits"`
+	PreviousCommits int    `json:"previousCommits"`
+}
+
+type trendsResponse struct {
+	Language    string       `json:"language"`
+	From        time.Time    `json:"from"`
+	To          time.Time    `json:"to"`
+	Changes     []rankChange `json:"changes"`
+	NewEntrants []rankChange `json:"newEntrants"`
+	DropOuts    []rankChange `json:"dropOuts"`
+}
+
+func newTrendsResponse(diff *app.SnapshotDiff) trendsResponse {
+	newRankChanges := func(changes []app.RankChange) []rankChange {
+		result := make([]rankChange, 0, len(changes))
+		for _, c := range changes {
+			result = append(result, rankChange{
+				Name:            c.Contributor.Login,
+				Rank:            c.Rank,
+				PreviousRank:    c.PreviousRank,
+				Climbed:         c.Climbed(),
+				Commits:         c.Commits,
+				PreviousCommits: c.PreviousCommits,
+			})
+		}
+		return result
+	}
+
+	return trendsResponse{
+		Language:    diff.Language,
+		From:        diff.From,
+		To:          diff.To,
+		Changes:     newRankChanges(diff.Changes),
+		NewEntrants: newRankChanges(diff.NewEntrants),
+		DropOuts:    newRankChanges(diff.DropOuts),
+	}
+}
+
+// NewTrendsHandler creates handlerfunc returning difference between two leaderboard snapshots.
+// Snapshots are selected by optional `from` and `to` params, latest two snapshots are compared by default.
+func NewTrendsHandler(
+	getLanguage func(*http.Request) string,
+	snapshotService SnapshotService,
+	l logrus.FieldLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lang := getLanguage(r)
+		from, err := getTimeParam(r, "from")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		to, err := getTimeParam(r, "to")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		diff, err := snapshotService.SnapshotDiff(r.Context(), lang, from, to)
+		if err != nil {
+			writeServiceError(w, err, l)
+			return
+		}
+
+		writeJSON(w, newTrendsResponse(diff))
+	}
+}
+
+type alias struct {
+	Alias string `json:"alias"`
+	Login string `json:"login"`
+}
+
+type aliasesResponse struct {
+	Aliases []alias `json:"aliases"`
+}
+
+// NewAliasesHandler creates handlerfunc managing contributors' aliases.
+// GET returns all aliases, PUT sets alias given in json body and DELETE removes alias given by `alias` param.
+// Aliases chains, cycles and conflicts with aliases from mapping file are rejected with 400 status code.
+func NewAliasesHandler(aliasService AliasService, l logrus.FieldLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			resp := aliasesResponse{
+				Aliases: make([]alias, 0),
+			}
+			for a, login := range aliasService.Aliases() {
+				resp.Aliases = append(resp.Aliases, alias{
+					Alias: a,
+					Login: login,
+				})
+			}
+			sort.Slice(resp.Aliases, func(i, j int) bool {
+				return resp.Aliases[i].Alias < resp.Aliases[j].Alias
+			})
+			writeJSON(w, resp)
+		case http.MethodPut:
+			var req alias
+			if err := json.NewDecoder(io.LimitReader(r.Body, 1024)).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
 				return
 			}
-			if app.IsTooManyRequestsError(err) {
-				http.Error(w, "", http.StatusTooManyRequests)
+			// Logins can't contain whitespace, surrounding one would make alias never match.
+			req.Alias = strings.TrimSpace(req.Alias)
+			req.Login = strings.TrimSpace(req.Login)
+			if req.Alias == "" || req.Login == "" {
+				http.Error(w, "alias and login are required", http.StatusBadRequest)
 				return
 			}
-			if app.IsScheduledForLaterError(err) {
-				http.Error(w, "", http.StatusAccepted)
+			if err := aliasService.SetAlias(req.Alias, req.Login); err != nil {
+				writeServiceError(w, err, l)
 				return
 			}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			if err := aliasService.DeleteAlias(r.URL.Query().Get("alias")); err != nil {
+				writeServiceError(w, err, l)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "", http.StatusMethodNotAllowed)
+		}
+	}
+}
 
-			http.Error(w, "", http.StatusInternalServerError)
-			l.Errorf("contributors http handler: service returned error: %v\n", err)
-			return
+// writeServiceError writes http response with status code matching given service error.
+func writeServiceError(w http.ResponseWriter, err error, l logrus.FieldLogger) {
+	if app.IsInvalidRequestError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if app.IsNotFoundError(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if app.IsTooManyRequestsError(err) {
+		http.Error(w, "", http.StatusTooManyRequests)
+		return
+	}
+	var rateLimitErr app.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		// Exhausted quota makes service unavailable for everyone until reset.
+		status := http.StatusTooManyRequests
+		if rateLimitErr.Exhausted {
+			status = http.StatusServiceUnavailable
 		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(rateLimitErr.RetryAfter)))
+		http.Error(w, "", status)
+		return
+	}
+	if app.IsScheduledForLaterError(err) {
+		http.Error(w, "", http.StatusAccepted)
+		return
+	}
+
+	http.Error(w, "", http.StatusInternalServerError)
+	l.Errorf("http handler: service returned error: %v\n", err)
+}
 
-		response := newContributorsResponse(lang, contributions)
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(response)
+}
 
-		w.Header().Set("Content-type", "application/json; charset=utf-8")
-		_ = json.NewEncoder(w).Encode(response)
+func aliasLogins(aliases []app.Contributor) []string {
+	var logins []string
+	for _, a := range aliases {
+		logins = append(logins, a.Login)
 	}
+	return logins
+}
+
+// retryAfterSeconds returns Retry-After header value for given duration, rounded up to full seconds.
+func retryAfterSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
 }
 
 func getIntParam(r *http.Request, name string, defaultValue int) int {
 	value := defaultValue
 	if vs := r.URL.Query().Get(name); vs != "" {
-		if v, err := strconv.Atoi(vs); err == nil && v > 0 && v < 100 {
+		if v, err := strconv.Atoi(vs); err == nil && v > 0 {
 			value = v
 		}
 	}
 
 	return value
 }
+
+// getListParam returns values of given param. Param can be repeated or contain comma separated values.
+func getListParam(r *http.Request, name string) []string {
+	var values []string
+	for _, v := range r.URL.Query()[name] {
+		for _, s := range strings.Split(v, ",") {
+			if s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+
+	return values
+}
+
+func getBoolParam(r *http.Request, name string) bool {
+	v, _ := strconv.ParseBool(r.URL.Query().Get(name))
+	return v
+}
+
+// getFloatParam parses non-negative, finite number param. Returns zero if param is empty.
+func getFloatParam(r *http.Request, name string) (float64, error) {
+	vs := r.URL.Query().Get(name)
+	if vs == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(vs, 64)
+	if err != nil || v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, app.InvalidRequestError(fmt.Sprintf("invalid %s param", name))
+	}
+
+	return v, nil
+}
+
+// getScoreWeights reads score ranking weights from `commitsWeight`, `additionsWeight` and `deletionsWeight`
+// query params. Missing weights are zero, see app.Ranking.
+func getScoreWeights(r *http.Request) (app.ScoreWeights, error) {
+	var (
+		weights app.ScoreWeights
+		err     error
+	)
+	if weights.Commits, err = getFloatParam(r, "commitsWeight"); err != nil {
+		return app.ScoreWeights{}, err
+	}
+	if weights.Additions, err = getFloatParam(r, "additionsWeight"); err != nil {
+		return app.ScoreWeights{}, err
+	}
+	if weights.Deletions, err = getFloatParam(r, "deletionsWeight"); err != nil {
+		return app.ScoreWeights{}, err
+	}
+
+	return weights, nil
+}
+
+// getProjectFilter reads project filter from query params.
+func getProjectFilter(r *http.Request) (app.ProjectFilter, error) {
+	pushedAfter, err := getTimeParam(r, "pushedAfter")
+	if err != nil {
+		return app.ProjectFilter{}, err
+	}
+	var minStars int
+	if vs := r.URL.Query().Get("minStars"); vs != "" {
+		if minStars, err = strconv.Atoi(vs); err != nil || minStars < 0 {
+			return app.ProjectFilter{}, app.InvalidRequestError("invalid minStars param")
+		}
+	}
+
+	return app.ProjectFilter{
+		MinStars:        minStars,
+		ExcludeForks:    getBoolParam(r, "excludeForks"),
+		ExcludeArchived: getBoolParam(r, "excludeArchived"),
+		Topics:          getListParam(r, "topic"),
+		PushedAfter:     pushedAfter,
+		License:         r.URL.Query().Get("license"),
+	}, nil
+}
+
+// getTimeWindow reads time window from `since`, `until` and `weeks` query params.
+func getTimeWindow(r *http.Request) (app.TimeWindow, error) {
+	since, err := getTimeParam(r, "since")
+	if err != nil {
+		return app.TimeWindow{}, err
+	}
+	until, err := getTimeParam(r, "until")
+	if err != nil {
+		return app.TimeWindow{}, err
+	}
+	var weeks int
+	if vs := r.URL.Query().Get("weeks"); vs != "" {
+		if weeks, err = strconv.Atoi(vs); err != nil {
+			return app.TimeWindow{}, app.InvalidRequestError("invalid weeks param")
+		}
+	}
+
+	return app.NewTimeWindow(since, until, weeks, time.Now())
+}
+
+// getTimeParam parses time param given as date (YYYY-MM-DD) or RFC 3339 timestamp.
+// Returns zero time if param is empty.
+func getTimeParam(r *http.Request, name string) (time.Time, error) {
+	vs := r.URL.Query().Get(name)
+	if vs == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, vs); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, app.InvalidRequestError(fmt.Sprintf("invalid %s param, expected YYYY-MM-DD or RFC 3339 time", name))
+}
@@ -2,21 +2,33 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/m-zajac/goprojectdemo/internal/api/http/mock"
 	"github.com/m-zajac/goprojectdemo/internal/app"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewContributorsHandler(t *testing.T) {
 	t.Parallel()
 
+	defaultQuery := app.ContributorsQuery{
+		Language:      "go",
+		ProjectsCount: defaultHandlerProjectsCountValue,
+		Count:         defaultHandlerCountValue,
+		Ranking: app.Ranking{
+			Mode: app.RankByCommits,
+		},
+	}
+
 	tests := []struct {
 		name            string
 		language        string
@@ -25,21 +37,22 @@ func TestNewContributorsHandler(t *testing.T) {
 		wantStatus      int
 		wantBody        string
 		wantContentType string
+		wantRetryAfter  string
 	}{
 		{
 			name:     "default params values",
 			language: "go",
 			setupMock: func(m *mock.MockService) {
 				m.EXPECT().
-					MostActiveContributors(gomock.Any(), "go", defaultHandlerProjectsCountValue, defaultHandlerCountValue).
-					Return(nil, nil)
+					MostActiveContributors(gomock.Any(), defaultQuery).
+					Return(&app.ContributorsResult{}, nil)
 			},
 			newRequest: func() *http.Request {
 				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
 				return r
 			},
 			wantStatus:      http.StatusOK,
-			wantBody:        `{"language":"go","contributors":[]}`,
+			wantBody:        `{"language":"go","contributors":[],"incomplete":false,"partial":false,"completeness":0,"filtered":0}`,
 			wantContentType: "application/json; charset=utf-8",
 		},
 		{
@@ -47,23 +60,210 @@ func TestNewContributorsHandler(t *testing.T) {
 			language: "go",
 			setupMock: func(m *mock.MockService) {
 				m.EXPECT().
-					MostActiveContributors(gomock.Any(), "go", 3, 7).
-					Return(nil, nil)
+					MostActiveContributors(gomock.Any(), app.ContributorsQuery{
+						Language:      "go",
+						ProjectsCount: 3,
+						Count:         7,
+						Ranking: app.Ranking{
+							Mode: app.RankByCommits,
+						},
+					}).
+					Return(&app.ContributorsResult{}, nil)
 			},
 			newRequest: func() *http.Request {
 				r, _ := http.NewRequest(http.MethodGet, "testurl?count=7&projectsCount=3", nil)
 				return r
 			},
 			wantStatus:      http.StatusOK,
-			wantBody:        `{"language":"go","contributors":[]}`,
+			wantBody:        `{"language":"go","contributors":[],"incomplete":false,"partial":false,"completeness":0,"filtered":0}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:     "ranking params from url query",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), app.ContributorsQuery{
+						Language:      "go",
+						ProjectsCount: defaultHandlerProjectsCountValue,
+						Count:         defaultHandlerCountValue,
+						Ranking: app.Ranking{
+							Mode: app.RankByScore,
+							Weights: app.ScoreWeights{
+								Commits:   2,
+								Additions: 0.5,
+							},
+						},
+					}).
+					Return(&app.ContributorsResult{}, nil)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?rankBy=score&commitsWeight=2&additionsWeight=0.5", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","contributors":[],"incomplete":false,"partial":false,"completeness":0,"filtered":0}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:     "forge param from url query",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), app.ContributorsQuery{
+						Forge:         app.ForgeGitlab,
+						Language:      "go",
+						ProjectsCount: defaultHandlerProjectsCountValue,
+						Count:         defaultHandlerCountValue,
+						Ranking: app.Ranking{
+							Mode: app.RankByCommits,
+						},
+					}).
+					Return(&app.ContributorsResult{}, nil)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?forge=gitlab", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","contributors":[],"incomplete":false,"partial":false,"completeness":0,"filtered":0}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:     "time window params from url query",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), app.ContributorsQuery{
+						Language:      "go",
+						ProjectsCount: defaultHandlerProjectsCountValue,
+						Count:         defaultHandlerCountValue,
+						Ranking: app.Ranking{
+							Mode: app.RankByCommits,
+						},
+						Window: app.TimeWindow{
+							Since: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+							Until: time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC),
+						},
+					}).
+					Return(&app.ContributorsResult{}, nil)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?since=2020-01-01&until=2020-03-01T12:00:00Z", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","contributors":[],"incomplete":false,"partial":false,"completeness":0,"filtered":0}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:     "invalid time window",
+			language: "go",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?since=2020-01-01&weeks=3", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `weeks and since cannot be used together`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "exclude params from url query",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				q := defaultQuery
+				q.Exclude = []string{"*-ci", "re:^test", "alice"}
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), q).
+					Return(&app.ContributorsResult{Filtered: 3}, nil)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?exclude=*-ci,re:^test&exclude=alice", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","contributors":[],"incomplete":false,"partial":false,"completeness":0,"filtered":3}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:     "project filter params from url query",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				q := defaultQuery
+				q.ProjectFilter = app.ProjectFilter{
+					MinStars:        1000,
+					ExcludeForks:    true,
+					ExcludeArchived: true,
+					Topics:          []string{"cli", "http"},
+					PushedAfter:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					License:         "mit",
+				}
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), q).
+					Return(&app.ContributorsResult{}, nil)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(
+					http.MethodGet,
+					"testurl?minStars=1000&excludeForks=true&excludeArchived=1&topic=cli,http&pushedAfter=2020-01-01&license=mit",
+					nil,
+				)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","contributors":[],"incomplete":false,"partial":false,"completeness":0,"filtered":0}`,
 			wantContentType: "application/json; charset=utf-8",
 		},
+		{
+			name:     "invalid project filter",
+			language: "go",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?minStars=-5", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `invalid minStars param`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "non-numeric score weight",
+			language: "go",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?rankBy=score&commitsWeight=abc", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `invalid commitsWeight param`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "negative score weight",
+			language: "go",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?rankBy=score&deletionsWeight=-1", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `invalid deletionsWeight param`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "invalid ranking mode",
+			language: "go",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?rankBy=stars", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `invalid ranking mode 'stars'`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
 		{
 			name:     "bad request",
 			language: "go",
 			setupMock: func(m *mock.MockService) {
 				m.EXPECT().
-					MostActiveContributors(gomock.Any(), "go", defaultHandlerProjectsCountValue, defaultHandlerCountValue).
+					MostActiveContributors(gomock.Any(), defaultQuery).
 					Return(nil, app.InvalidRequestError("invalid params"))
 			},
 			newRequest: func() *http.Request {
@@ -74,12 +274,67 @@ func TestNewContributorsHandler(t *testing.T) {
 			wantBody:        `invalid params`,
 			wantContentType: "text/plain; charset=utf-8",
 		},
+		{
+			name:     "not found",
+			language: "nolang",
+			setupMock: func(m *mock.MockService) {
+				q := defaultQuery
+				q.Language = "nolang"
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), q).
+					Return(nil, fmt.Errorf("wrapped: %w", app.NotFoundError("language not found")))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusNotFound,
+			wantBody:        `wrapped: language not found`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "upstream rate limit exhausted",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), defaultQuery).
+					Return(nil, fmt.Errorf("wrapped: %w", app.RateLimitError{
+						RetryAfter: 1500 * time.Millisecond,
+						Exhausted:  true,
+					}))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusServiceUnavailable,
+			wantBody:        ``,
+			wantContentType: "text/plain; charset=utf-8",
+			wantRetryAfter:  "2",
+		},
+		{
+			name:     "upstream rate limit exceeded",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), defaultQuery).
+					Return(nil, app.RateLimitError{RetryAfter: time.Minute})
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusTooManyRequests,
+			wantBody:        ``,
+			wantContentType: "text/plain; charset=utf-8",
+			wantRetryAfter:  "60",
+		},
 		{
 			name:     "service error",
 			language: "go",
 			setupMock: func(m *mock.MockService) {
 				m.EXPECT().
-					MostActiveContributors(gomock.Any(), "go", defaultHandlerProjectsCountValue, defaultHandlerCountValue).
+					MostActiveContributors(gomock.Any(), defaultQuery).
 					Return(nil, errors.New("error"))
 			},
 			newRequest: func() *http.Request {
@@ -94,16 +349,119 @@ func TestNewContributorsHandler(t *testing.T) {
 			language: "go",
 			setupMock: func(m *mock.MockService) {
 				m.EXPECT().
-					MostActiveContributors(gomock.Any(), "go", defaultHandlerProjectsCountValue, defaultHandlerCountValue).
+					MostActiveContributors(gomock.Any(), defaultQuery).
 					Return(
-						[]app.ContributorStats{
-							{
-								Commits: 5,
-								Contributor: app.Contributor{
-									ID:    1,
-									Login: "tester",
+						&app.ContributorsResult{
+							Stats: []app.ContributorStats{
+								{
+									Commits:   5,
+									Additions: 100,
+									Deletions: 20,
+									Contributor: app.Contributor{
+										ID:    1,
+										Login: "tester",
+									},
+									Projects: []app.ProjectContribution{
+										{
+											Project: app.Project{
+												ID:         1,
+												Name:       "project1",
+												OwnerLogin: "owner",
+											},
+											Commits: 4,
+										},
+										{
+											Project: app.Project{
+												ID:         2,
+												Name:       "project2",
+												OwnerLogin: "owner",
+											},
+											Commits: 1,
+										},
+									},
+								},
+							},
+						},
+						nil,
+					)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","contributors":[{"name":"tester","commits":5,"additions":100,"deletions":20,"projects":[{"project":"owner/project1","commits":4},{"project":"owner/project2","commits":1}]}],"incomplete":false,"partial":false,"completeness":0,"filtered":0}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:     "incomplete response in best effort mode",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				q := defaultQuery
+				q.BestEffort = true
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), q).
+					Return(
+						&app.ContributorsResult{
+							Stats: []app.ContributorStats{
+								{
+									Commits: 5,
+									Contributor: app.Contributor{
+										ID:    1,
+										Login: "tester",
+									},
+								},
+							},
+							Skipped: []app.SkippedProject{
+								{
+									Project: app.Project{
+										ID:         1,
+										Name:       "project",
+										OwnerLogin: "owner",
+									},
+									Reason: app.SkipReasonScheduled,
+								},
+							},
+						},
+						nil,
+					)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?bestEffort=true", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","contributors":[{"name":"tester","commits":5,"additions":0,"deletions":0}],"incomplete":true,"partial":false,"completeness":0,"skipped":[{"project":"owner/project","reason":"scheduled"}],"filtered":0}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:     "partial response in degraded mode",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					MostActiveContributors(gomock.Any(), defaultQuery).
+					Return(
+						&app.ContributorsResult{
+							Stats: []app.ContributorStats{
+								{
+									Commits: 5,
+									Contributor: app.Contributor{
+										ID:    1,
+										Login: "tester",
+									},
+								},
+							},
+							Skipped: []app.SkippedProject{
+								{
+									Project: app.Project{
+										ID:         1,
+										Name:       "project",
+										OwnerLogin: "owner",
+									},
+									Reason: app.SkipReasonTimeout,
 								},
 							},
+							Completeness: 0.5,
 						},
 						nil,
 					)
@@ -113,7 +471,7 @@ func TestNewContributorsHandler(t *testing.T) {
 				return r
 			},
 			wantStatus:      http.StatusOK,
-			wantBody:        `{"language":"go","contributors":[{"name":"tester","commits":5}]}`,
+			wantBody:        `{"language":"go","contributors":[{"name":"tester","commits":5,"additions":0,"deletions":0}],"incomplete":true,"partial":true,"completeness":0.5,"skipped":[{"project":"owner/project","reason":"timeout"}],"filtered":0}`,
 			wantContentType: "application/json; charset=utf-8",
 		},
 	}
@@ -140,6 +498,670 @@ func TestNewContributorsHandler(t *testing.T) {
 
 			handler(w, req)
 
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-type"))
+			assert.Equal(t, tt.wantRetryAfter, w.Header().Get("Retry-After"))
+
+			body := w.Body.String()
+			body = strings.Trim(body, "\n")
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestNewContributorProfileHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		login           string
+		setupMock       func(*mock.MockService)
+		newRequest      func() *http.Request
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+	}{
+		{
+			name:  "bad request",
+			login: "",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					ContributorProfile(gomock.Any(), "", "", "go", defaultHandlerProjectsCountValue).
+					Return(nil, app.InvalidRequestError("login cannot be empty"))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?language=go", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `login cannot be empty`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:  "scheduled for later",
+			login: "tester",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					ContributorProfile(gomock.Any(), app.ForgeGitlab, "tester", "go", 3).
+					Return(nil, app.ScheduledForLaterError("scheduled"))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?language=go&projectsCount=3&forge=gitlab", nil)
+				return r
+			},
+			wantStatus:      http.StatusAccepted,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:  "contributor not found",
+			login: "tester",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					ContributorProfile(gomock.Any(), "", "tester", "go", defaultHandlerProjectsCountValue).
+					Return(nil, app.NotFoundError("contributor 'tester' has no commits in top go projects"))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?language=go", nil)
+				return r
+			},
+			wantStatus:      http.StatusNotFound,
+			wantBody:        `contributor 'tester' has no commits in top go projects`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:  "valid response",
+			login: "tester",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					ContributorProfile(gomock.Any(), "", "tester", "go", defaultHandlerProjectsCountValue).
+					Return(
+						&app.ContributorProfile{
+							Contributor: app.Contributor{
+								ID:    1,
+								Login: "Tester",
+							},
+							Language:          "go",
+							Rank:              2,
+							ContributorsCount: 10,
+							Commits:           5,
+							Additions:         50,
+							Deletions:         5,
+							Projects: []app.ProjectContribution{
+								{
+									Project: app.Project{
+										ID:         1,
+										Name:       "project",
+										OwnerLogin: "owner",
+									},
+									Commits: 5,
+								},
+							},
+							Aliases: []app.Contributor{
+								{
+									ID:    2,
+									Login: "tester-work",
+								},
+							},
+						},
+						nil,
+					)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?language=go", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"login":"Tester","language":"go","rank":2,"contributorsCount":10,"commits":5,"additions":50,"deletions":5,"projects":[{"project":"owner/project","commits":5}],"aliases":["tester-work"]}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockService(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(s)
+			}
+
+			l := logrus.New()
+			handler := NewContributorProfileHandler(
+				func(*http.Request) string {
+					return tt.login
+				},
+				s,
+				l,
+			)
+			req := tt.newRequest()
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-type"))
+
+			body := w.Body.String()
+			body = strings.Trim(body, "\n")
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestNewProjectsHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		language        string
+		setupMock       func(*mock.MockService)
+		newRequest      func() *http.Request
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+	}{
+		{
+			name:     "invalid project filter",
+			language: "go",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?pushedAfter=yesterday", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `invalid pushedAfter param, expected YYYY-MM-DD or RFC 3339 time`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "service error",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					TopProjects(gomock.Any(), app.ProjectsQuery{Language: "go"}, defaultHandlerProjectsCountValue).
+					Return(nil, errors.New("error"))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "valid response",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					TopProjects(
+						gomock.Any(),
+						app.ProjectsQuery{
+							Forge:    app.ForgeGitlab,
+							Language: "go",
+							Filter: app.ProjectFilter{
+								ExcludeArchived: true,
+							},
+						},
+						3,
+					).
+					Return(
+						[]app.Project{
+							{
+								ID:            1,
+								Name:          "project",
+								OwnerLogin:    "owner",
+								Stars:         100,
+								Forks:         10,
+								Description:   "Test project",
+								URL:           "https://github.com/owner/project",
+								DefaultBranch: "main",
+								PushedAt:      time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC),
+								License:       "MIT",
+							},
+						},
+						nil,
+					)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?count=3&excludeArchived=true&forge=gitlab", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","projects":[{"name":"owner/project","url":"https://github.com/owner/project","description":"Test project","stars":100,"forks":10,"defaultBranch":"main","pushedAt":"2020-05-01T10:00:00Z","license":"MIT"}]}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockService(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(s)
+			}
+
+			l := logrus.New()
+			handler := NewProjectsHandler(
+				func(*http.Request) string {
+					return tt.language
+				},
+				s,
+				l,
+			)
+			req := tt.newRequest()
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-type"))
+
+			body := w.Body.String()
+			body = strings.Trim(body, "\n")
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestNewTrendsHandler(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		language        string
+		setupMock       func(*mock.MockSnapshotService)
+		newRequest      func() *http.Request
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+	}{
+		{
+			name:     "invalid time param",
+			language: "go",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?to=tomorrow", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `invalid to param, expected YYYY-MM-DD or RFC 3339 time`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "not enough snapshots",
+			language: "go",
+			setupMock: func(m *mock.MockSnapshotService) {
+				m.EXPECT().
+					SnapshotDiff(gomock.Any(), "go", time.Time{}, time.Time{}).
+					Return(nil, app.InvalidRequestError("not enough snapshots"))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `not enough snapshots`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "valid response",
+			language: "go",
+			setupMock: func(m *mock.MockSnapshotService) {
+				m.EXPECT().
+					SnapshotDiff(gomock.Any(), "go", t1, t2).
+					Return(
+						&app.SnapshotDiff{
+							Language: "go",
+							From:     t1,
+							To:       t2,
+							Changes: []app.RankChange{
+								{
+									Contributor:     app.Contributor{ID: 1, Login: "climber"},
+									PreviousRank:    3,
+									Rank:            1,
+									PreviousCommits: 5,
+									Commits:         20,
+								},
+							},
+							NewEntrants: []app.RankChange{
+								{
+									Contributor: app.Contributor{ID: 2, Login: "new"},
+									Rank:        2,
+									Commits:     10,
+								},
+							},
+						},
+						nil,
+					)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?from=2020-01-01&to=2020-01-08", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","from":"2020-01-01T00:00:00Z","to":"2020-01-08T00:00:00Z","changes":[{"name":"climber","rank":1,"previousRank":3,"climbed":2,"commits":20,"previousCommits":5}],"newEntrants":[{"name":"new","rank":2,"previousRank":0,"climbed":0,"commits":10,"previousCommits":0}],"dropOuts":[]}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockSnapshotService(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(s)
+			}
+
+			l := logrus.New()
+			handler := NewTrendsHandler(
+				func(*http.Request) string {
+					return tt.language
+				},
+				s,
+				l,
+			)
+			req := tt.newRequest()
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-type"))
+
+			body := w.Body.String()
+			body = strings.Trim(body, "\n")
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestNewAliasesHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		setupMock       func(*mock.MockAliasService)
+		newRequest      func() *http.Request
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+	}{
+		{
+			name: "list aliases",
+			setupMock: func(m *mock.MockAliasService) {
+				m.EXPECT().
+					Aliases().
+					Return(map[string]string{
+						"b-work": "b",
+						"a-work": "a",
+					})
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"aliases":[{"alias":"a-work","login":"a"},{"alias":"b-work","login":"b"}]}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name: "set alias",
+			setupMock: func(m *mock.MockAliasService) {
+				m.EXPECT().
+					SetAlias("a-work", "a").
+					Return(nil)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodPut, "testurl", strings.NewReader(`{"alias":"a-work","login":"a"}`))
+				return r
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "set invalid alias",
+			setupMock: func(m *mock.MockAliasService) {
+				m.EXPECT().
+					SetAlias("a", "a").
+					Return(app.InvalidRequestError("login 'a' cannot be its own alias"))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodPut, "testurl", strings.NewReader(`{"alias":"a","login":"a"}`))
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `login 'a' cannot be its own alias`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name: "set alias without login",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodPut, "testurl", strings.NewReader(`{"alias":"a-work","login":" "}`))
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `alias and login are required`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name: "set alias with invalid body",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodPut, "testurl", strings.NewReader(`{`))
+				return r
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantBody:        `invalid request body`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name: "delete alias",
+			setupMock: func(m *mock.MockAliasService) {
+				m.EXPECT().
+					DeleteAlias("a-work").
+					Return(nil)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodDelete, "testurl?alias=a-work", nil)
+				return r
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "invalid method",
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodPost, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusMethodNotAllowed,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockAliasService(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(s)
+			}
+
+			l := logrus.New()
+			handler := NewAliasesHandler(s, l)
+			req := tt.newRequest()
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-type"))
+
+			body := w.Body.String()
+			body = strings.Trim(body, "\n")
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestNewAliasesHandlerValidation(t *testing.T) {
+	t.Parallel()
+
+	resolver, err := app.NewIdentityResolver(map[string][]string{"a": {"a-work"}}, nil)
+	require.NoError(t, err)
+	handler := NewAliasesHandler(resolver, logrus.New())
+
+	// Requests are sent in order to the same resolver.
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "valid alias",
+			body:       `{"alias":" b-work ","login":"b"}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "chain",
+			body:       `{"alias":"c","login":"b-work"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "cycle",
+			body:       `{"alias":"b","login":"b-work"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "own alias",
+			body:       `{"alias":"B","login":"b"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "alias from mapping file",
+			body:       `{"alias":"a-work","login":"b"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "alias of alias from mapping file",
+			body:       `{"alias":"x","login":"a-work"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "main identity from mapping file",
+			body:       `{"alias":"a","login":"b"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodPut, "testurl", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		assert.Equal(t, tt.wantStatus, w.Code, tt.name)
+	}
+	assert.Equal(t, map[string]string{"a-work": "a", "b-work": "b"}, resolver.Aliases())
+}
+
+func TestNewHealthMetricsHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		language        string
+		setupMock       func(*mock.MockService)
+		newRequest      func() *http.Request
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+	}{
+		{
+			name:     "scheduled for later",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					HealthMetrics(gomock.Any(), "", "go", defaultHandlerProjectsCountValue).
+					Return(nil, app.ScheduledForLaterError("scheduled"))
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+				return r
+			},
+			wantStatus:      http.StatusAccepted,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:     "valid response",
+			language: "go",
+			setupMock: func(m *mock.MockService) {
+				m.EXPECT().
+					HealthMetrics(gomock.Any(), "", "go", 3).
+					Return(
+						&app.HealthMetrics{
+							Language: "go",
+							Overall: app.ConcentrationMetrics{
+								Contributors: 2,
+								Commits:      4,
+								BusFactor:    1,
+								Gini:         0.25,
+								Top1Share:    0.75,
+								Top5Share:    1,
+								Top10Share:   1,
+							},
+							Projects: []app.ProjectHealth{
+								{
+									Project: app.Project{
+										ID:         1,
+										Name:       "project",
+										OwnerLogin: "owner",
+									},
+									Metrics: app.ConcentrationMetrics{
+										Contributors: 1,
+										Commits:      3,
+										BusFactor:    1,
+										Top1Share:    1,
+										Top5Share:    1,
+										Top10Share:   1,
+									},
+								},
+							},
+						},
+						nil,
+					)
+			},
+			newRequest: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "testurl?projectsCount=3", nil)
+				return r
+			},
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"language":"go","overall":{"contributors":2,"commits":4,"busFactor":1,"gini":0.25,"top1Share":0.75,"top5Share":1,"top10Share":1},"projects":[{"project":"owner/project","contributors":1,"commits":3,"busFactor":1,"gini":0,"top1Share":1,"top5Share":1,"top10Share":1}]}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockService(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(s)
+			}
+
+			l := logrus.New()
+			handler := NewHealthMetricsHandler(
+				func(*http.Request) string {
+					return tt.language
+				},
+				s,
+				l,
+			)
+			req := tt.newRequest()
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
 			assert.Equal(t, tt.wantStatus, w.Code)
 			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-type"))
 
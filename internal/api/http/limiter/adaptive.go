@@ -0,0 +1,264 @@
+package limiter
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"golang.org/x/time/rate"
+)
+
+// Api resources with separate rate limits.
+const (
+	resourceCore    = "core"
+	resourceSearch  = "search"
+	resourceGraphQL = "graphql"
+)
+
+// adaptiveHTTPDoer wraps HTTPDoer and paces requests, so remaining api quota reported in responses' headers
+// is spread evenly until quota's reset. Each api resource has its own quota.
+// Quota headers of github and gitlab are supported, see parseRateLimit.
+// See: https://docs.github.com/en/rest/overview/resources-in-the-rest-api#rate-limiting
+type adaptiveHTTPDoer struct {
+	doer    HTTPDoer
+	limiter *rate.Limiter
+	// maxWait is the maximum time request without deadline waits for its turn.
+	maxWait time.Duration
+
+	m       sync.Mutex
+	buckets map[string]*quotaBucket
+}
+
+// quotaBucket holds state of api resource's quota.
+type quotaBucket struct {
+	// remaining is the number of requests left until reset, -1 if unknown.
+	remaining int
+	reset     time.Time
+	// blockedUntil is set by Retry-After header, when secondary rate limit is hit.
+	blockedUntil time.Time
+	// next is the time next request can be sent.
+	next time.Time
+}
+
+// NewAdaptiveHTTPDoer creates HTTPDoer pacing requests by api quota reported in responses.
+// maxRate - maximum number of Dos per second, regardless of the quota.
+//
+// If request can't be sent before its context's deadline, app.RateLimitError is returned.
+func NewAdaptiveHTTPDoer(doer HTTPDoer, maxRate float64) HTTPDoer {
+	return newAdaptiveHTTPDoer(doer, maxRate)
+}
+
+func newAdaptiveHTTPDoer(doer HTTPDoer, maxRate float64) *adaptiveHTTPDoer {
+	return &adaptiveHTTPDoer{
+		doer:    doer,
+		limiter: rate.NewLimiter(rate.Limit(maxRate), 1),
+		maxWait: time.Minute,
+		buckets: make(map[string]*quotaBucket),
+	}
+}
+
+// Do executes http request. Blocks until request's turn comes.
+func (d *adaptiveHTTPDoer) Do(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	resource := requestResource(r)
+
+	wait, err := d.reserve(resource, ctx.Deadline)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, app.TooManyRequestsError(fmt.Sprintf("waiting for api quota: %v", ctx.Err()))
+		}
+	}
+	if err := d.limiter.Wait(ctx); err != nil {
+		return nil, app.TooManyRequestsError(fmt.Sprintf("waiting for httpDoer limiter: %v", err))
+	}
+
+	resp, err := d.doer.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	d.update(resource, resp)
+
+	return resp, nil
+}
+
+// reserve reserves slot for a request to given resource, and returns time left to the slot.
+// Returns app.RateLimitError if slot is after request's deadline.
+func (d *adaptiveHTTPDoer) reserve(resource string, deadline func() (time.Time, bool)) (time.Duration, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	now := time.Now()
+	b := d.bucket(resource, now)
+
+	start := now
+	if b.next.After(start) {
+		start = b.next
+	}
+	if b.blockedUntil.After(start) {
+		start = b.blockedUntil
+	}
+	exhausted := b.remaining == 0
+	if exhausted && b.reset.After(start) {
+		start = b.reset
+	}
+
+	latest, ok := deadline()
+	if !ok {
+		latest = now.Add(d.maxWait)
+	}
+	if start.After(latest) {
+		return 0, app.RateLimitError{
+			RetryAfter: start.Sub(now),
+			Exhausted:  exhausted,
+		}
+	}
+
+	b.next = start
+	if b.remaining > 0 {
+		// Remaining quota is spread evenly until the reset.
+		b.next = start.Add(b.reset.Sub(start) / time.Duration(b.remaining))
+		b.remaining--
+	}
+
+	return start.Sub(now), nil
+}
+
+// update updates resource's quota by response headers.
+func (d *adaptiveHTTPDoer) update(resource string, resp *http.Response) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	now := time.Now()
+	b := d.bucket(resource, now)
+
+	if remaining, reset, ok := parseRateLimit(resp.Header); ok {
+		switch {
+		case reset.After(b.reset):
+			b.remaining = remaining
+			b.reset = reset
+		case reset.Equal(b.reset) && remaining < b.remaining:
+			// Responses can come out of order, the lowest remaining value is the latest one.
+			b.remaining = remaining
+		}
+	}
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter, ok := parseRetryAfter(resp.Header); ok {
+			if until := now.Add(retryAfter); until.After(b.blockedUntil) {
+				b.blockedUntil = until
+			}
+		}
+	}
+}
+
+// quota returns state of given resource's quota.
+func (d *adaptiveHTTPDoer) quota(resource string) ResourceQuota {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	return d.bucket(resource, time.Now()).quota()
+}
+
+// quotas returns state of quotas of all used resources.
+func (d *adaptiveHTTPDoer) quotas() map[string]ResourceQuota {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	now := time.Now()
+	quotas := make(map[string]ResourceQuota, len(d.buckets))
+	for resource := range d.buckets {
+		quotas[resource] = d.bucket(resource, now).quota()
+	}
+
+	return quotas
+}
+
+// bucket returns quota bucket for given resource. Quota with passed reset time is unknown again.
+// Must be called with d.m locked.
+func (d *adaptiveHTTPDoer) bucket(resource string, now time.Time) *quotaBucket {
+	b, ok := d.buckets[resource]
+	if !ok {
+		b = &quotaBucket{remaining: -1}
+		d.buckets[resource] = b
+	}
+	if b.remaining >= 0 && !b.reset.After(now) {
+		b.remaining = -1
+	}
+
+	return b
+}
+
+// ResourceQuota describes state of api resource's quota.
+type ResourceQuota struct {
+	// Remaining is the number of requests left until reset, -1 if unknown.
+	Remaining int `json:"remaining"`
+	// AvailableAt is the time from which resource can be used again. Zero if resource is available.
+	AvailableAt time.Time `json:"availableAt"`
+}
+
+func (b *quotaBucket) quota() ResourceQuota {
+	q := ResourceQuota{
+		Remaining: b.remaining,
+	}
+	if b.blockedUntil.After(time.Now()) {
+		q.AvailableAt = b.blockedUntil
+	}
+	if b.remaining == 0 && b.reset.After(q.AvailableAt) {
+		q.AvailableAt = b.reset
+	}
+
+	return q
+}
+
+// requestResource returns api resource requested by r.
+func requestResource(r *http.Request) string {
+	switch {
+	case strings.Contains(r.URL.Path, "/search/"):
+		return resourceSearch
+	case strings.HasSuffix(r.URL.Path, "/graphql"):
+		return resourceGraphQL
+	default:
+		return resourceCore
+	}
+}
+
+// parseRateLimit returns remaining quota and quota's reset time from response headers.
+// Github reports quota in X-RateLimit-Remaining and X-RateLimit-Reset headers, gitlab in RateLimit-Remaining
+// and RateLimit-Reset headers. Reset is unix time in both cases.
+// See: https://docs.gitlab.com/ee/administration/settings/user_and_ip_rate_limits.html#response-headers
+func parseRateLimit(h http.Header) (int, time.Time, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remaining, err := strconv.Atoi(h.Get(prefix + "Remaining"))
+		if err != nil || remaining < 0 {
+			continue
+		}
+		reset, err := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64)
+		if err != nil {
+			continue
+		}
+		return remaining, time.Unix(reset, 0), true
+	}
+
+	return 0, time.Time{}, false
+}
+
+// parseRetryAfter returns duration from Retry-After header. Only delay in seconds is supported.
+func parseRetryAfter(h http.Header) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,229 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/m-zajac/goprojectdemo/internal/mock"
+)
+
+func TestAdaptiveHTTPDoerPacing(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(2*time.Second).Unix(), 10)
+	doer := &mock.HTTPDoer{
+		DoFunc: func(r *http.Request) (*http.Response, error) {
+			return newRateLimitResponse(http.StatusOK, http.Header{
+				"X-Ratelimit-Remaining": []string{"20"},
+				"X-Ratelimit-Reset":     []string{reset},
+			}), nil
+		},
+	}
+	adaptiveDoer := NewAdaptiveHTTPDoer(doer, 1000)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://fake/repos/a/b", nil)
+	// First response tells remaining quota.
+	if _, err := adaptiveDoer.Do(req); err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+
+	// 20 requests are left for at least 1 second, so there should be at least 50ms between them.
+	startTime := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := adaptiveDoer.Do(req); err != nil {
+			t.Fatalf("Do() returned error: %v", err)
+		}
+	}
+	if elapsed := time.Since(startTime); elapsed < 90*time.Millisecond || elapsed > time.Second {
+		t.Errorf("unexpected time of 3 Dos: %v", elapsed)
+	}
+}
+
+func TestAdaptiveHTTPDoerExhaustedQuota(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	var calls int
+	doer := &mock.HTTPDoer{
+		DoFunc: func(r *http.Request) (*http.Response, error) {
+			calls++
+			return newRateLimitResponse(http.StatusOK, http.Header{
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{reset},
+			}), nil
+		},
+	}
+	adaptiveDoer := NewAdaptiveHTTPDoer(doer, 1000)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://fake/repos/a/b", nil)
+	ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+	defer cancel()
+	req = req.WithContext(ctx)
+	if _, err := adaptiveDoer.Do(req); err != nil {
+		t.Fatalf("first Do() returned error: %v", err)
+	}
+
+	_, err := adaptiveDoer.Do(req)
+	var rateLimitErr app.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("second Do() returned unexpected error: %v", err)
+	}
+	if !rateLimitErr.Exhausted {
+		t.Error("quota should be reported as exhausted")
+	}
+	if rateLimitErr.RetryAfter < 59*time.Minute {
+		t.Errorf("unexpected retry after: %v", rateLimitErr.RetryAfter)
+	}
+
+	// Search quota is separate.
+	searchReq, _ := http.NewRequest(http.MethodGet, "https://fake/search/repositories", nil)
+	if _, err := adaptiveDoer.Do(searchReq.WithContext(ctx)); err != nil {
+		t.Fatalf("search Do() returned error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("unexpected number of calls: %d", calls)
+	}
+}
+
+func TestAdaptiveHTTPDoerGitlabQuota(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	var calls int
+	doer := &mock.HTTPDoer{
+		DoFunc: func(r *http.Request) (*http.Response, error) {
+			calls++
+			return newRateLimitResponse(http.StatusOK, http.Header{
+				"Ratelimit-Limit":     []string{"2000"},
+				"Ratelimit-Remaining": []string{"0"},
+				"Ratelimit-Reset":     []string{reset},
+			}), nil
+		},
+	}
+	adaptiveDoer := NewAdaptiveHTTPDoer(doer, 1000)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://fake/api/v4/projects", nil)
+	ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+	defer cancel()
+	req = req.WithContext(ctx)
+	if _, err := adaptiveDoer.Do(req); err != nil {
+		t.Fatalf("first Do() returned error: %v", err)
+	}
+
+	_, err := adaptiveDoer.Do(req)
+	var rateLimitErr app.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("second Do() returned unexpected error: %v", err)
+	}
+	if !rateLimitErr.Exhausted {
+		t.Error("quota should be reported as exhausted")
+	}
+	if calls != 1 {
+		t.Errorf("unexpected number of calls: %d", calls)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        http.Header
+		wantRemaining int
+		wantReset     int64
+		wantOK        bool
+	}{
+		{
+			name:   "no headers",
+			header: http.Header{},
+		},
+		{
+			name: "github headers",
+			header: http.Header{
+				"X-Ratelimit-Remaining": []string{"10"},
+				"X-Ratelimit-Reset":     []string{"1600000000"},
+			},
+			wantRemaining: 10,
+			wantReset:     1600000000,
+			wantOK:        true,
+		},
+		{
+			name: "gitlab headers",
+			header: http.Header{
+				"Ratelimit-Remaining": []string{"5"},
+				"Ratelimit-Reset":     []string{"1600000060"},
+			},
+			wantRemaining: 5,
+			wantReset:     1600000060,
+			wantOK:        true,
+		},
+		{
+			name: "invalid reset",
+			header: http.Header{
+				"Ratelimit-Remaining": []string{"5"},
+				"Ratelimit-Reset":     []string{"soon"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, reset, ok := parseRateLimit(tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("unexpected ok: %v", ok)
+			}
+			if !ok {
+				return
+			}
+			if remaining != tt.wantRemaining {
+				t.Errorf("unexpected remaining: %d", remaining)
+			}
+			if reset.Unix() != tt.wantReset {
+				t.Errorf("unexpected reset: %v", reset)
+			}
+		})
+	}
+}
+
+func TestAdaptiveHTTPDoerRetryAfter(t *testing.T) {
+	var calls int
+	doer := &mock.HTTPDoer{
+		DoFunc: func(r *http.Request) (*http.Response, error) {
+			calls++
+			return newRateLimitResponse(http.StatusForbidden, http.Header{
+				"Retry-After": []string{"30"},
+			}), nil
+		},
+	}
+	adaptiveDoer := NewAdaptiveHTTPDoer(doer, 1000)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://fake/repos/a/b", nil)
+	ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+	defer cancel()
+	req = req.WithContext(ctx)
+	if _, err := adaptiveDoer.Do(req); err != nil {
+		t.Fatalf("first Do() returned error: %v", err)
+	}
+
+	_, err := adaptiveDoer.Do(req)
+	var rateLimitErr app.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("second Do() returned unexpected error: %v", err)
+	}
+	if rateLimitErr.Exhausted {
+		t.Error("quota shouldn't be reported as exhausted")
+	}
+	if rateLimitErr.RetryAfter < 29*time.Second || rateLimitErr.RetryAfter > 30*time.Second {
+		t.Errorf("unexpected retry after: %v", rateLimitErr.RetryAfter)
+	}
+	if calls != 1 {
+		t.Errorf("unexpected number of calls: %d", calls)
+	}
+}
+
+func newRateLimitResponse(status int, header http.Header) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Header:     header,
+	}
+}
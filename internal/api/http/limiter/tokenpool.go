@@ -0,0 +1,139 @@
+package limiter
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenPool is a HTTPDoer authorizing requests with tokens from the pool.
+// Each request uses available token with the most remaining quota. Token is taken out of rotation while its quota
+// is exhausted, or for a while after it's rejected by the api. Requests with each token are paced like NewAdaptiveHTTPDoer does.
+type TokenPool struct {
+	tokens []*poolToken
+	// unauthorizedBackoff is the time token is out of rotation after it's rejected by the api.
+	unauthorizedBackoff time.Duration
+
+	m sync.Mutex
+}
+
+type poolToken struct {
+	token string
+	doer  *adaptiveHTTPDoer
+	// unauthorizedUntil is guarded by TokenPool.m.
+	unauthorizedUntil time.Time
+}
+
+// TokenHealth describes state of a token in the pool.
+type TokenHealth struct {
+	// Index is the token's position in the pool. Tokens themselves are never exposed.
+	Index int `json:"index"`
+	// Unauthorized tells if token was recently rejected by the api.
+	Unauthorized bool `json:"unauthorized"`
+	// Quotas holds state of token's quota by api resource. Only resources requested with the token are listed.
+	Quotas map[string]ResourceQuota `json:"quotas"`
+}
+
+// NewTokenPool creates TokenPool instance.
+// maxRate - maximum number of Dos per second with a single token.
+func NewTokenPool(doer HTTPDoer, tokens []string, maxRate float64) (*TokenPool, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("token pool requires at least one token")
+	}
+
+	p := TokenPool{
+		unauthorizedBackoff: 10 * time.Minute,
+	}
+	for _, token := range tokens {
+		if token == "" {
+			return nil, errors.New("token pool can't contain empty token")
+		}
+		p.tokens = append(p.tokens, &poolToken{
+			token: token,
+			doer:  newAdaptiveHTTPDoer(doer, maxRate),
+		})
+	}
+
+	return &p, nil
+}
+
+// Do executes http request with the best available token. Blocks until request's turn comes.
+func (p *TokenPool) Do(r *http.Request) (*http.Response, error) {
+	t := p.pick(requestResource(r))
+
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	resp, err := t.doer.Do(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		p.m.Lock()
+		t.unauthorizedUntil = time.Now().Add(p.unauthorizedBackoff)
+		p.m.Unlock()
+	}
+
+	return resp, nil
+}
+
+// Health returns state of tokens in the pool.
+func (p *TokenPool) Health() []TokenHealth {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	now := time.Now()
+	health := make([]TokenHealth, 0, len(p.tokens))
+	for i, t := range p.tokens {
+		health = append(health, TokenHealth{
+			Index:        i,
+			Unauthorized: t.unauthorizedUntil.After(now),
+			Quotas:       t.doer.quotas(),
+		})
+	}
+
+	return health
+}
+
+// pick returns available token with the most remaining quota for given resource.
+// Token with unknown quota is preferred, as its quota is probably full.
+// If no token is available, returns the one which becomes available first.
+func (p *TokenPool) pick(resource string) *poolToken {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	now := time.Now()
+	var best, earliest *poolToken
+	var earliestAt time.Time
+	bestRemaining := -1
+	for _, t := range p.tokens {
+		q := t.doer.quota(resource)
+		availableAt := q.AvailableAt
+		if t.unauthorizedUntil.After(availableAt) {
+			availableAt = t.unauthorizedUntil
+		}
+		if availableAt.After(now) {
+			if earliest == nil || availableAt.Before(earliestAt) {
+				earliest = t
+				earliestAt = availableAt
+			}
+			continue
+		}
+
+		remaining := q.Remaining
+		if remaining < 0 {
+			remaining = math.MaxInt32
+		}
+		if remaining > bestRemaining {
+			best = t
+			bestRemaining = remaining
+		}
+	}
+	if best == nil {
+		return earliest
+	}
+
+	return best
+}
@@ -0,0 +1,124 @@
+package limiter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/mock"
+)
+
+func TestTokenPool(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(2*time.Second).Unix(), 10)
+	tests := []struct {
+		name       string
+		responses  map[string]*http.Response
+		wantTokens []string
+		wantHealth []TokenHealth
+	}{
+		{
+			name: "token with most remaining quota is used",
+			responses: map[string]*http.Response{
+				"tokenA": newRateLimitResponse(http.StatusOK, http.Header{
+					"X-Ratelimit-Remaining": []string{"10"},
+					"X-Ratelimit-Reset":     []string{reset},
+				}),
+				"tokenB": newRateLimitResponse(http.StatusOK, http.Header{
+					"X-Ratelimit-Remaining": []string{"100"},
+					"X-Ratelimit-Reset":     []string{reset},
+				}),
+			},
+			wantTokens: []string{"tokenA", "tokenB", "tokenB"},
+			wantHealth: []TokenHealth{
+				{Index: 0, Quotas: map[string]ResourceQuota{"core": {Remaining: 10}}},
+				{Index: 1, Quotas: map[string]ResourceQuota{"core": {Remaining: 99}}},
+			},
+		},
+		{
+			name: "unauthorized token is taken out of rotation",
+			responses: map[string]*http.Response{
+				"tokenA": newRateLimitResponse(http.StatusUnauthorized, http.Header{}),
+				"tokenB": newRateLimitResponse(http.StatusOK, http.Header{}),
+			},
+			wantTokens: []string{"tokenA", "tokenB", "tokenB"},
+			wantHealth: []TokenHealth{
+				{Index: 0, Unauthorized: true, Quotas: map[string]ResourceQuota{"core": {Remaining: -1}}},
+				{Index: 1, Quotas: map[string]ResourceQuota{"core": {Remaining: -1}}},
+			},
+		},
+		{
+			name: "rate limited token is taken out of rotation",
+			responses: map[string]*http.Response{
+				"tokenA": newRateLimitResponse(http.StatusForbidden, http.Header{
+					"Retry-After": []string{"60"},
+				}),
+				"tokenB": newRateLimitResponse(http.StatusOK, http.Header{}),
+			},
+			wantTokens: []string{"tokenA", "tokenB", "tokenB"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m sync.Mutex
+			var tokens []string
+			doer := &mock.HTTPDoer{
+				DoFunc: func(r *http.Request) (*http.Response, error) {
+					m.Lock()
+					defer m.Unlock()
+
+					token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+					tokens = append(tokens, token)
+					return tt.responses[token], nil
+				},
+			}
+			pool, err := NewTokenPool(doer, []string{"tokenA", "tokenB"}, 1000)
+			if err != nil {
+				t.Fatalf("NewTokenPool() returned error: %v", err)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "https://fake/repos/a/b", nil)
+			for range tt.wantTokens {
+				if _, err := pool.Do(req); err != nil {
+					t.Fatalf("Do() returned error: %v", err)
+				}
+			}
+			if strings.Join(tokens, ",") != strings.Join(tt.wantTokens, ",") {
+				t.Errorf("unexpected tokens used: %v, want %v", tokens, tt.wantTokens)
+			}
+			if req.Header.Get("Authorization") != "" {
+				t.Error("original request was modified")
+			}
+
+			if tt.wantHealth == nil {
+				return
+			}
+			health := pool.Health()
+			if len(health) != len(tt.wantHealth) {
+				t.Fatalf("unexpected health: %+v", health)
+			}
+			for i, h := range health {
+				want := tt.wantHealth[i]
+				if h.Index != want.Index || h.Unauthorized != want.Unauthorized || len(h.Quotas) != len(want.Quotas) {
+					t.Errorf("unexpected health of token %d: %+v, want %+v", i, h, want)
+				}
+				for resource, q := range want.Quotas {
+					if h.Quotas[resource].Remaining != q.Remaining {
+						t.Errorf("unexpected remaining quota of token %d: %d, want %d", i, h.Quotas[resource].Remaining, q.Remaining)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNewTokenPoolInvalidTokens(t *testing.T) {
+	if _, err := NewTokenPool(&mock.HTTPDoer{}, nil, 1); err == nil {
+		t.Error("expected error for empty pool")
+	}
+	if _, err := NewTokenPool(&mock.HTTPDoer{}, []string{"a", ""}, 1); err == nil {
+		t.Error("expected error for empty token")
+	}
+}
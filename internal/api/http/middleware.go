@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
 	"time"
 )
@@ -18,3 +19,19 @@ func NewTimeoutMiddleware(timeout time.Duration) func(http.HandlerFunc) http.Han
 		}
 	}
 }
+
+// NewAuthMiddleware creates middleware that rejects requests without given bearer token.
+func NewAuthMiddleware(token string) func(http.HandlerFunc) http.HandlerFunc {
+	want := []byte("Bearer " + token)
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			got := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(got, want) != 1 {
+				http.Error(w, "", http.StatusUnauthorized)
+				return
+			}
+
+			h(w, r)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package http
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewTimeoutMiddleware(t *testing.T) {
@@ -23,3 +26,45 @@ func TestNewTimeoutMiddleware(t *testing.T) {
 	r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
 	m(h)(nil, r)
 }
+
+func TestNewAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	m := NewAuthMiddleware("secret")
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{
+			name:       "no token",
+			header:     "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid token",
+			header:     "Bearer public",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid token",
+			header:     "Bearer secret",
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, "testurl", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			m(h)(w, r)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/m-zajac/goprojectdemo/internal/api/http (interfaces: AliasService)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAliasService is a mock of AliasService interface
+type MockAliasService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasServiceMockRecorder
+}
+
+// MockAliasServiceMockRecorder is the mock recorder for MockAliasService
+type MockAliasServiceMockRecorder struct {
+	mock *MockAliasService
+}
+
+// NewMockAliasService creates a new mock instance
+func NewMockAliasService(ctrl *gomock.Controller) *MockAliasService {
+	mock := &MockAliasService{ctrl: ctrl}
+	mock.recorder = &MockAliasServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAliasService) EXPECT() *MockAliasServiceMockRecorder {
+	return m.recorder
+}
+
+// Aliases mocks base method
+func (m *MockAliasService) Aliases() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Aliases")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// Aliases indicates an expected call of Aliases
+func (mr *MockAliasServiceMockRecorder) Aliases() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aliases", reflect.TypeOf((*MockAliasService)(nil).Aliases))
+}
+
+// DeleteAlias mocks base method
+func (m *MockAliasService) DeleteAlias(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias
+func (mr *MockAliasServiceMockRecorder) DeleteAlias(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockAliasService)(nil).DeleteAlias), arg0)
+}
+
+// SetAlias mocks base method
+func (m *MockAliasService) SetAlias(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlias", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAlias indicates an expected call of SetAlias
+func (mr *MockAliasServiceMockRecorder) SetAlias(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlias", reflect.TypeOf((*MockAliasService)(nil).SetAlias), arg0, arg1)
+}
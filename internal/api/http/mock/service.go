@@ -34,17 +34,62 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// ContributorProfile mocks base method
+func (m *MockService) ContributorProfile(arg0 context.Context, arg1, arg2, arg3 string, arg4 int) (*app.ContributorProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContributorProfile", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*app.ContributorProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContributorProfile indicates an expected call of ContributorProfile
+func (mr *MockServiceMockRecorder) ContributorProfile(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContributorProfile", reflect.TypeOf((*MockService)(nil).ContributorProfile), arg0, arg1, arg2, arg3, arg4)
+}
+
+// HealthMetrics mocks base method
+func (m *MockService) HealthMetrics(arg0 context.Context, arg1, arg2 string, arg3 int) (*app.HealthMetrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthMetrics", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*app.HealthMetrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HealthMetrics indicates an expected call of HealthMetrics
+func (mr *MockServiceMockRecorder) HealthMetrics(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthMetrics", reflect.TypeOf((*MockService)(nil).HealthMetrics), arg0, arg1, arg2, arg3)
+}
+
 // MostActiveContributors mocks base method
-func (m *MockService) MostActiveContributors(arg0 context.Context, arg1 string, arg2, arg3 int) ([]app.ContributorStats, error) {
+func (m *MockService) MostActiveContributors(arg0 context.Context, arg1 app.ContributorsQuery) (*app.ContributorsResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MostActiveContributors", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].([]app.ContributorStats)
+	ret := m.ctrl.Call(m, "MostActiveContributors", arg0, arg1)
+	ret0, _ := ret[0].(*app.ContributorsResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // MostActiveContributors indicates an expected call of MostActiveContributors
-func (mr *MockServiceMockRecorder) MostActiveContributors(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) MostActiveContributors(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MostActiveContributors", reflect.TypeOf((*MockService)(nil).MostActiveContributors), arg0, arg1)
+}
+
+// TopProjects mocks base method
+func (m *MockService) TopProjects(arg0 context.Context, arg1 app.ProjectsQuery, arg2 int) ([]app.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopProjects", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]app.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopProjects indicates an expected call of TopProjects
+func (mr *MockServiceMockRecorder) TopProjects(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MostActiveContributors", reflect.TypeOf((*MockService)(nil).MostActiveContributors), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopProjects", reflect.TypeOf((*MockService)(nil).TopProjects), arg0, arg1, arg2)
 }
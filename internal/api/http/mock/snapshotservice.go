@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/m-zajac/goprojectdemo/internal/api/http (interfaces: SnapshotService)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	app "github.com/m-zajac/goprojectdemo/internal/app"
+	reflect "reflect"
+	time "time"
+)
+
+// MockSnapshotService is a mock of SnapshotService interface
+type MockSnapshotService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotServiceMockRecorder
+}
+
+// MockSnapshotServiceMockRecorder is the mock recorder for MockSnapshotService
+type MockSnapshotServiceMockRecorder struct {
+	mock *MockSnapshotService
+}
+
+// NewMockSnapshotService creates a new mock instance
+func NewMockSnapshotService(ctrl *gomock.Controller) *MockSnapshotService {
+	mock := &MockSnapshotService{ctrl: ctrl}
+	mock.recorder = &MockSnapshotServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSnapshotService) EXPECT() *MockSnapshotServiceMockRecorder {
+	return m.recorder
+}
+
+// SnapshotDiff mocks base method
+func (m *MockSnapshotService) SnapshotDiff(arg0 context.Context, arg1 string, arg2, arg3 time.Time) (*app.SnapshotDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotDiff", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*app.SnapshotDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotDiff indicates an expected call of SnapshotDiff
+func (mr *MockSnapshotServiceMockRecorder) SnapshotDiff(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotDiff", reflect.TypeOf((*MockSnapshotService)(nil).SnapshotDiff), arg0, arg1, arg2, arg3)
+}
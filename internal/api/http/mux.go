@@ -10,19 +10,45 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// Service can return most active contributors.
+// Service can return most active contributors, contributor profiles, top projects and their health metrics.
 //go:generate mockgen -destination mock/service.go -package mock github.com/m-zajac/goprojectdemo/internal/api/http Service
 type Service interface {
-	MostActiveContributors(
+	MostActiveContributors(ctx context.Context, q app.ContributorsQuery) (*app.ContributorsResult, error)
+	ContributorProfile(
 		ctx context.Context,
+		forge string,
+		login string,
 		language string,
 		projectsCount int,
-		count int,
-	) ([]app.ContributorStats, error)
+	) (*app.ContributorProfile, error)
+	TopProjects(ctx context.Context, q app.ProjectsQuery, count int) ([]app.Project, error)
+	HealthMetrics(ctx context.Context, forge string, language string, projectsCount int) (*app.HealthMetrics, error)
+}
+
+// SnapshotService can compare leaderboard snapshots.
+//go:generate mockgen -destination mock/snapshotservice.go -package mock github.com/m-zajac/goprojectdemo/internal/api/http SnapshotService
+type SnapshotService interface {
+	SnapshotDiff(ctx context.Context, language string, from, to time.Time) (*app.SnapshotDiff, error)
+}
+
+// AliasService can manage contributors' aliases.
+//go:generate mockgen -destination mock/aliasservice.go -package mock github.com/m-zajac/goprojectdemo/internal/api/http AliasService
+type AliasService interface {
+	Aliases() map[string]string
+	SetAlias(alias string, login string) error
+	DeleteAlias(alias string) error
 }
 
 // NewMux creates router for app's http server.
-func NewMux(service Service, timeout time.Duration, l logrus.FieldLogger) *http.ServeMux {
+// Admin endpoints are registered only if adminToken is not empty.
+func NewMux(
+	service Service,
+	snapshotService SnapshotService,
+	aliasService AliasService,
+	adminToken string,
+	timeout time.Duration,
+	l logrus.FieldLogger,
+) *http.ServeMux {
 	timeoutMiddleware := NewTimeoutMiddleware(timeout)
 
 	contributorsPath := "/bestcontributors/"
@@ -35,8 +61,58 @@ func NewMux(service Service, timeout time.Duration, l logrus.FieldLogger) *http.
 	)
 	contributorsHandler = timeoutMiddleware(contributorsHandler)
 
+	profilePath := "/contributors/"
+	profileHandler := NewContributorProfileHandler(
+		func(r *http.Request) string {
+			return strings.TrimPrefix(r.URL.Path, profilePath)
+		},
+		service,
+		l.WithField("handler", "contributorProfileHandler"),
+	)
+	profileHandler = timeoutMiddleware(profileHandler)
+
+	projectsPath := "/projects/"
+	projectsHandler := NewProjectsHandler(
+		func(r *http.Request) string {
+			return strings.TrimPrefix(r.URL.Path, projectsPath)
+		},
+		service,
+		l.WithField("handler", "projectsHandler"),
+	)
+	projectsHandler = timeoutMiddleware(projectsHandler)
+
+	healthPath := "/health-metrics/"
+	healthHandler := NewHealthMetricsHandler(
+		func(r *http.Request) string {
+			return strings.TrimPrefix(r.URL.Path, healthPath)
+		},
+		service,
+		l.WithField("handler", "healthMetricsHandler"),
+	)
+	healthHandler = timeoutMiddleware(healthHandler)
+
+	trendsPath := "/trends/"
+	trendsHandler := NewTrendsHandler(
+		func(r *http.Request) string {
+			return strings.TrimPrefix(r.URL.Path, trendsPath)
+		},
+		snapshotService,
+		l.WithField("handler", "trendsHandler"),
+	)
+	trendsHandler = timeoutMiddleware(trendsHandler)
+
 	m := http.NewServeMux()
 	m.HandleFunc(contributorsPath, contributorsHandler)
+	m.HandleFunc(profilePath, profileHandler)
+	m.HandleFunc(projectsPath, projectsHandler)
+	m.HandleFunc(healthPath, healthHandler)
+	m.HandleFunc(trendsPath, trendsHandler)
+
+	if adminToken != "" {
+		authMiddleware := NewAuthMiddleware(adminToken)
+		aliasesHandler := NewAliasesHandler(aliasService, l.WithField("handler", "aliasesHandler"))
+		m.HandleFunc("/admin/aliases", authMiddleware(aliasesHandler))
+	}
 
 	return m
 }
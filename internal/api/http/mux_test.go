@@ -2,15 +2,21 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/m-zajac/goprojectdemo/internal/adapter/forge"
+	forgemock "github.com/m-zajac/goprojectdemo/internal/adapter/forge/mock"
 	"github.com/m-zajac/goprojectdemo/internal/api/http/mock"
 	"github.com/m-zajac/goprojectdemo/internal/app"
+	appmock "github.com/m-zajac/goprojectdemo/internal/app/mock"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +30,7 @@ func TestMux(t *testing.T) {
 	tests := []struct {
 		name           string
 		path           string
+		token          string
 		muxTimeout     time.Duration
 		wantStatusCode int
 	}{
@@ -39,6 +46,49 @@ func TestMux(t *testing.T) {
 			muxTimeout:     time.Microsecond,
 			wantStatusCode: http.StatusInternalServerError,
 		},
+		{
+			name:           "valid contributor profile request",
+			path:           "/contributors/tester?language=go",
+			muxTimeout:     time.Second,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "valid projects request",
+			path:           "/projects/go",
+			muxTimeout:     time.Second,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "valid health metrics request",
+			path:           "/health-metrics/go",
+			muxTimeout:     time.Second,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "valid trends request",
+			path:           "/trends/go",
+			muxTimeout:     time.Second,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "debug vars are not public",
+			path:           "/debug/vars",
+			muxTimeout:     time.Second,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "admin request without token",
+			path:           "/admin/aliases",
+			muxTimeout:     time.Second,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "admin request with token",
+			path:           "/admin/aliases",
+			token:          "secret",
+			muxTimeout:     time.Second,
+			wantStatusCode: http.StatusOK,
+		},
 		{
 			name:           "invalid path",
 			path:           "/invalid_path",
@@ -54,34 +104,209 @@ func TestMux(t *testing.T) {
 
 			service := mock.NewMockService(ctrl)
 			service.EXPECT().
-				MostActiveContributors(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-				DoAndReturn(func(
-					ctx context.Context,
-					language string,
-					projectsCount int,
-					count int,
-				) ([]app.ContributorStats, error) {
+				MostActiveContributors(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, q app.ContributorsQuery) (*app.ContributorsResult, error) {
 					time.Sleep(serviceDelay)
 
 					select {
 					case <-ctx.Done():
 						return nil, errors.New("context timeout")
 					default:
-						return nil, nil
+						return &app.ContributorsResult{}, nil
 					}
 				}).
 				MaxTimes(1)
+			service.EXPECT().
+				ContributorProfile(gomock.Any(), "", "tester", "go", gomock.Any()).
+				Return(&app.ContributorProfile{}, nil).
+				MaxTimes(1)
+			service.EXPECT().
+				TopProjects(gomock.Any(), app.ProjectsQuery{Language: "go"}, gomock.Any()).
+				Return([]app.Project{}, nil).
+				MaxTimes(1)
+			service.EXPECT().
+				HealthMetrics(gomock.Any(), "", "go", gomock.Any()).
+				Return(&app.HealthMetrics{}, nil).
+				MaxTimes(1)
+
+			snapshotService := mock.NewMockSnapshotService(ctrl)
+			snapshotService.EXPECT().
+				SnapshotDiff(gomock.Any(), "go", time.Time{}, time.Time{}).
+				Return(&app.SnapshotDiff{}, nil).
+				MaxTimes(1)
+
+			aliasService := mock.NewMockAliasService(ctrl)
+			aliasService.EXPECT().
+				Aliases().
+				Return(map[string]string{}).
+				MaxTimes(1)
 
 			l := logrus.New()
-			mux := NewMux(service, tt.muxTimeout, l)
+			mux := NewMux(service, snapshotService, aliasService, "secret", tt.muxTimeout, l)
 
 			server := httptest.NewServer(mux)
 			defer server.Close()
 
-			url := server.URL + tt.path
-			resp, err := http.Get(url)
+			req, err := http.NewRequest(http.MethodGet, server.URL+tt.path, nil)
+			require.NoError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			resp, err := http.DefaultClient.Do(req)
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantStatusCode, resp.StatusCode)
 		})
 	}
 }
+
+func TestMuxDegradedService(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	forgeCli := appmock.NewMockForgeClient(ctrl)
+	forgeCli.EXPECT().
+		ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, gomock.Any()).
+		Return(
+			[]app.Project{
+				{ID: 1, Name: "project1", OwnerLogin: "owner"},
+				{ID: 2, Name: "project2", OwnerLogin: "owner"},
+			},
+			nil,
+		)
+	forgeCli.EXPECT().
+		StatsByProject(gomock.Any(), "project1", "owner").
+		Return(
+			[]app.ContributorStats{
+				{Commits: 3, Contributor: app.Contributor{ID: 1, Login: "cont1"}},
+			},
+			nil,
+		)
+	forgeCli.EXPECT().
+		StatsByProject(gomock.Any(), "project2", "owner").
+		DoAndReturn(func(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	// Stats are gathered until 100ms before handler's timeout.
+	service := app.NewService(app.Forges{app.DefaultForge: forgeCli}, nil, nil, nil, time.Minute, 100*time.Millisecond)
+	mux := NewMux(service, nil, nil, "", 200*time.Millisecond, logrus.New())
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/bestcontributors/go?projectsCount=2")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body contributorsResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.True(t, body.Partial)
+	assert.Equal(t, 0.5, body.Completeness)
+	assert.Equal(t, []skippedProject{{Project: "owner/project2", Reason: string(app.SkipReasonTimeout)}}, body.Skipped)
+	require.Len(t, body.Contributors, 1)
+	assert.Equal(t, "cont1", body.Contributors[0].Name)
+}
+
+func TestMuxUpstreamRateLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		projectsErr   error
+		statsErr      error
+		wantResponses []int
+	}{
+		{
+			name:          "exhausted search quota",
+			projectsErr:   app.RateLimitError{RetryAfter: time.Minute, Exhausted: true},
+			wantResponses: []int{http.StatusAccepted, http.StatusServiceUnavailable},
+		},
+		{
+			name:          "throttled stats requests",
+			statsErr:      app.RateLimitError{RetryAfter: time.Minute},
+			wantResponses: []int{http.StatusAccepted, http.StatusAccepted, http.StatusTooManyRequests},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			forgeCli := appmock.NewMockForgeClient(ctrl)
+			forgeCli.EXPECT().
+				ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 1).
+				Return([]app.Project{{ID: 1, Name: "project", OwnerLogin: "owner"}}, tt.projectsErr).
+				Times(1)
+			forgeCli.EXPECT().
+				StatsByProject(gomock.Any(), "project", "owner").
+				Return(nil, tt.statsErr).
+				MaxTimes(1)
+
+			l := logrus.New()
+			l.Out = ioutil.Discard
+			schedulerLog := logrus.New()
+			schedulerLog.Out = ioutil.Discard
+			updatesDone := make(updatesDoneHook, len(tt.wantResponses))
+			schedulerLog.AddHook(updatesDone)
+			staleDataClient, err := forge.NewClientWithStaleData(
+				forgeCli,
+				forgemock.NewKVStore(nil, nil),
+				time.Hour,
+				time.Hour,
+				schedulerLog,
+			)
+			require.NoError(t, err)
+			staleDataClient.RunScheduler()
+			defer staleDataClient.Close()
+
+			service := app.NewService(app.Forges{app.DefaultForge: staleDataClient}, nil, nil, nil, time.Minute, 0)
+			server := httptest.NewServer(NewMux(service, nil, nil, "", time.Second, l))
+			defer server.Close()
+
+			var resp *http.Response
+			for i, want := range tt.wantResponses {
+				// Previous response scheduled upstream api call, its result decides about the next response.
+				if i > 0 {
+					select {
+					case <-updatesDone:
+					case <-time.After(time.Second):
+						t.Fatalf("scheduled update before response %d not finished", i)
+					}
+				}
+
+				resp, err = http.Get(server.URL + "/bestcontributors/go?projectsCount=1")
+				require.NoError(t, err)
+				resp.Body.Close()
+				require.Equal(t, want, resp.StatusCode, "response %d", i)
+			}
+			assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+		})
+	}
+}
+
+// updatesDoneHook signals finished scheduled updates of forge.ClientWithStaleData, as they're logged
+// after results of upstream api calls are recorded.
+type updatesDoneHook chan struct{}
+
+func (h updatesDoneHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h updatesDoneHook) Fire(e *logrus.Entry) error {
+	if e.Level != logrus.ErrorLevel && !strings.HasSuffix(e.Message, " done") {
+		return nil
+	}
+	// Signals nobody waits for are dropped, not to block the scheduler.
+	select {
+	case h <- struct{}{}:
+	default:
+	}
+	return nil
+}
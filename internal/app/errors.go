@@ -1,6 +1,10 @@
 package app
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // InvalidRequestError is special error type returned when any request params are invalid.
 type InvalidRequestError string
@@ -30,6 +34,34 @@ func IsInvalidRequestError(err error) bool {
 	return false
 }
 
+// NotFoundError is special error type returned when requested resource doesn't exist.
+type NotFoundError string
+
+// Error implements error interface.
+func (e NotFoundError) Error() string {
+	return string(e)
+}
+
+// IsNotFound tells that this error is 'not found'.
+// Returns always true.
+func (NotFoundError) IsNotFound() bool {
+	return true
+}
+
+// IsNotFoundError checks if given error is caused by missing resource.
+func IsNotFoundError(err error) bool {
+	type notFoundErr interface {
+		IsNotFound() bool
+	}
+
+	var ie notFoundErr
+	if errors.As(err, &ie) {
+		return ie.IsNotFound()
+	}
+
+	return false
+}
+
 // TooManyRequestsError is special error type returned when there's too many request to handle at a time.
 type TooManyRequestsError string
 
@@ -58,6 +90,29 @@ func IsTooManyRequestsError(err error) bool {
 	return false
 }
 
+// RateLimitError is special error type returned when upstream api's rate limit doesn't allow to make a request in time.
+type RateLimitError struct {
+	// RetryAfter is the duration after which the request can be retried.
+	RetryAfter time.Duration
+	// Exhausted tells if api quota is exhausted until its reset.
+	// Otherwise requests are only throttled, to keep their rate within the limit.
+	Exhausted bool
+}
+
+// Error implements error interface.
+func (e RateLimitError) Error() string {
+	if e.Exhausted {
+		return fmt.Sprintf("api rate limit exhausted, retry after %v", e.RetryAfter)
+	}
+	return fmt.Sprintf("api rate limit exceeded, retry after %v", e.RetryAfter)
+}
+
+// IsRateLimitError checks if given error is caused by upstream api's rate limit.
+func IsRateLimitError(err error) bool {
+	var re RateLimitError
+	return errors.As(err, &re)
+}
+
 // ScheduledForLaterError is special error type returned request could not be immediately processed and is scheduled for later.
 type ScheduledForLaterError string
 
@@ -85,3 +140,38 @@ func IsScheduledForLaterError(err error) bool {
 
 	return false
 }
+
+// UpstreamComputingError is special error type returned when upstream api is still computing requested data.
+// It's also a 'scheduled for later' error, data should be requested again later.
+type UpstreamComputingError string
+
+// Error implements error interface.
+func (e UpstreamComputingError) Error() string {
+	return string(e)
+}
+
+// IsUpstreamComputing tells that this error means 'upstream computing'.
+// Returns always true.
+func (UpstreamComputingError) IsUpstreamComputing() bool {
+	return true
+}
+
+// IsScheduledForLater tells that this error means 'scheduled for later processing'.
+// Returns always true.
+func (UpstreamComputingError) IsScheduledForLater() bool {
+	return true
+}
+
+// IsUpstreamComputingError checks if given error means 'upstream computing'.
+func IsUpstreamComputingError(err error) bool {
+	type upstreamComputingErr interface {
+		IsUpstreamComputing() bool
+	}
+
+	var ie upstreamComputingErr
+	if errors.As(err, &ie) {
+		return ie.IsUpstreamComputing()
+	}
+
+	return false
+}
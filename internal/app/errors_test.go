@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -18,3 +19,38 @@ func TestIsInvalidRequestError(t *testing.T) {
 	wrapperErr := fmt.Errorf("wrapping message: %w", irErr)
 	assert.True(t, IsInvalidRequestError(wrapperErr))
 }
+
+func TestIsNotFoundError(t *testing.T) {
+	stdErr := errors.New("simple error")
+	assert.False(t, IsNotFoundError(stdErr))
+
+	nfErr := NotFoundError("not found")
+	assert.True(t, IsNotFoundError(nfErr))
+
+	wrapperErr := fmt.Errorf("wrapping message: %w", nfErr)
+	assert.True(t, IsNotFoundError(wrapperErr))
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	stdErr := errors.New("simple error")
+	assert.False(t, IsRateLimitError(stdErr))
+
+	rlErr := RateLimitError{RetryAfter: time.Minute, Exhausted: true}
+	assert.True(t, IsRateLimitError(rlErr))
+
+	wrapperErr := fmt.Errorf("wrapping message: %w", rlErr)
+	assert.True(t, IsRateLimitError(wrapperErr))
+}
+
+func TestIsUpstreamComputingError(t *testing.T) {
+	stdErr := errors.New("simple error")
+	assert.False(t, IsUpstreamComputingError(stdErr))
+
+	ucErr := UpstreamComputingError("computing")
+	assert.True(t, IsUpstreamComputingError(ucErr))
+	assert.True(t, IsScheduledForLaterError(ucErr))
+
+	wrapperErr := fmt.Errorf("wrapping message: %w", ucErr)
+	assert.True(t, IsUpstreamComputingError(wrapperErr))
+	assert.False(t, IsUpstreamComputingError(ScheduledForLaterError("scheduled")))
+}
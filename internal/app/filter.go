@@ -0,0 +1,131 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// regexpPatternPrefix marks login pattern as regular expression. Patterns without it are globs.
+const regexpPatternPrefix = "re:"
+
+// knownBots are logins of well known automation accounts, not following bot naming conventions.
+var knownBots = map[string]bool{
+	"dependabot":  true,
+	"renovate":    true,
+	"greenkeeper": true,
+	"web-flow":    true,
+	// Placeholder for deleted github accounts.
+	"ghost": true,
+}
+
+// botSuffixes are common suffixes of automation accounts logins.
+var botSuffixes = []string{"[bot]", "-bot", "_bot", "-robot"}
+
+// IsBot tells if given login looks like an automation account.
+func IsBot(login string) bool {
+	login = strings.ToLower(login)
+	if knownBots[login] {
+		return true
+	}
+	for _, suffix := range botSuffixes {
+		if strings.HasSuffix(login, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoginFilter decides which contributors are excluded from rankings.
+//
+// Patterns are case insensitive globs (e.g. "*-ci"), or regular expressions prefixed with "re:" (e.g. "re:^test\d+$").
+// Logins matching allow list are exempt from filter's own rules, but per-query patterns take precedence over it,
+// so a caller can exclude any login. Nil filter excludes only logins matching per-query patterns.
+type LoginFilter struct {
+	excludeBots bool
+	allow       []loginPattern
+	deny        []loginPattern
+}
+
+// NewLoginFilter creates new LoginFilter instance.
+// If excludeBots is true, logins detected by IsBot are excluded.
+func NewLoginFilter(excludeBots bool, allow []string, deny []string) (*LoginFilter, error) {
+	allowPatterns, err := compileLoginPatterns(allow)
+	if err != nil {
+		return nil, fmt.Errorf("compiling allow list: %w", err)
+	}
+	denyPatterns, err := compileLoginPatterns(deny)
+	if err != nil {
+		return nil, fmt.Errorf("compiling deny list: %w", err)
+	}
+
+	return &LoginFilter{
+		excludeBots: excludeBots,
+		allow:       allowPatterns,
+		deny:        denyPatterns,
+	}, nil
+}
+
+// excluded tells if contributor with given login should be excluded.
+// Additional patterns are taken into account along with filter's deny list, and aren't overridden by allow list.
+func (f *LoginFilter) excluded(login string, additional []loginPattern) bool {
+	if matchesAny(login, additional) {
+		return true
+	}
+	if f == nil || matchesAny(login, f.allow) {
+		return false
+	}
+
+	return (f.excludeBots && IsBot(login)) || matchesAny(login, f.deny)
+}
+
+type loginPattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+func compileLoginPatterns(patterns []string) ([]loginPattern, error) {
+	result := make([]loginPattern, 0, len(patterns))
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		if strings.HasPrefix(p, regexpPatternPrefix) {
+			re, err := regexp.Compile(strings.TrimPrefix(p, regexpPatternPrefix))
+			if err != nil {
+				return nil, InvalidRequestError(fmt.Sprintf("invalid login pattern '%s': %v", p, err))
+			}
+			result = append(result, loginPattern{re: re})
+			continue
+		}
+
+		glob := strings.ToLower(p)
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, InvalidRequestError(fmt.Sprintf("invalid login pattern '%s': %v", p, err))
+		}
+		result = append(result, loginPattern{glob: glob})
+	}
+
+	return result, nil
+}
+
+func (p loginPattern) match(login string) bool {
+	if p.re != nil {
+		return p.re.MatchString(login)
+	}
+	ok, _ := path.Match(p.glob, strings.ToLower(login))
+	return ok
+}
+
+func matchesAny(login string, patterns []loginPattern) bool {
+	for _, p := range patterns {
+		if p.match(login) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBot(t *testing.T) {
+	bots := []string{"dependabot[bot]", "renovate-bot", "ghost", "Dependabot", "k8s-ci-robot", "some_bot"}
+	for _, login := range bots {
+		assert.True(t, IsBot(login), login)
+	}
+
+	humans := []string{"robert", "abbot", "bottle", "m-zajac"}
+	for _, login := range humans {
+		assert.False(t, IsBot(login), login)
+	}
+}
+
+func TestNewLoginFilter(t *testing.T) {
+	_, err := NewLoginFilter(true, []string{"re:("}, nil)
+	assert.Error(t, err)
+
+	_, err = NewLoginFilter(true, nil, []string{"[a-"})
+	assert.Error(t, err)
+
+	_, err = NewLoginFilter(true, []string{"re:^x$"}, []string{"*-ci", " "})
+	assert.NoError(t, err)
+}
+
+func TestLoginFilterExcluded(t *testing.T) {
+	filter, err := NewLoginFilter(true, []string{"good-bot"}, []string{"*-CI", "re:^test[0-9]+$"})
+	require.NoError(t, err)
+	additional, err := compileLoginPatterns([]string{"alice"})
+	require.NoError(t, err)
+	allowedAdditional, err := compileLoginPatterns([]string{"good-*"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		filter     *LoginFilter
+		login      string
+		additional []loginPattern
+		want       bool
+	}{
+		{
+			name:   "regular login",
+			filter: filter,
+			login:  "bob",
+			want:   false,
+		},
+		{
+			name:   "bot",
+			filter: filter,
+			login:  "dependabot[bot]",
+			want:   true,
+		},
+		{
+			name:   "bot on allow list",
+			filter: filter,
+			login:  "good-bot",
+			want:   false,
+		},
+		{
+			name:   "glob deny list, case insensitive",
+			filter: filter,
+			login:  "build-ci",
+			want:   true,
+		},
+		{
+			name:   "regexp deny list",
+			filter: filter,
+			login:  "test42",
+			want:   true,
+		},
+		{
+			name:       "additional pattern",
+			filter:     filter,
+			login:      "Alice",
+			additional: additional,
+			want:       true,
+		},
+		{
+			name:       "additional pattern wins over allow list",
+			filter:     filter,
+			login:      "good-bot",
+			additional: allowedAdditional,
+			want:       true,
+		},
+		{
+			name:   "nil filter",
+			filter: nil,
+			login:  "dependabot[bot]",
+			want:   false,
+		},
+		{
+			name:       "nil filter with additional pattern",
+			filter:     nil,
+			login:      "alice",
+			additional: additional,
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.excluded(tt.login, tt.additional))
+		})
+	}
+}
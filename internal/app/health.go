@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"sort"
+)
+
+// ConcentrationMetrics describe how commits are distributed among contributors.
+type ConcentrationMetrics struct {
+	Contributors int
+	Commits      int
+	// BusFactor is the smallest number of contributors responsible for at least half of the commits.
+	BusFactor int
+	// Gini is the Gini coefficient of commits distribution.
+	// Zero means equal distribution, values close to one mean commits concentrated in a single contributor.
+	Gini float64
+	// TopNShare fields hold the share of commits made by top N contributors, in range <0..1>.
+	Top1Share  float64
+	Top5Share  float64
+	Top10Share float64
+}
+
+// ProjectHealth holds concentration metrics of a single project.
+type ProjectHealth struct {
+	Project Project
+	Metrics ConcentrationMetrics
+}
+
+// HealthMetrics holds concentration metrics of language's top projects.
+type HealthMetrics struct {
+	Language string
+	// Overall holds metrics computed from contributors' commits in all projects.
+	Overall ConcentrationMetrics
+	// Projects are ordered by the number of stars.
+	Projects []ProjectHealth
+}
+
+// HealthMetrics returns contribution concentration metrics for top `projectsCount` projects
+// by the number of stars, and for all these projects as a whole.
+// Filtered out contributors are not taken into account, aliases are merged.
+// Empty forge means DefaultForge.
+func (s *Service) HealthMetrics(
+	ctx context.Context,
+	forge string,
+	language string,
+	projectsCount int,
+) (*HealthMetrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.requestTimeout)
+	defer cancel()
+
+	projects, result, err := s.contributorsStats(ctx, ContributorsQuery{
+		Forge:         forge,
+		Language:      language,
+		ProjectsCount: projectsCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	overall := make([]int, 0, len(result.Stats))
+	perProject := make(map[int][]int)
+	for _, st := range result.Stats {
+		overall = append(overall, st.Commits)
+		for _, pc := range st.Projects {
+			perProject[pc.Project.ID] = append(perProject[pc.Project.ID], pc.Commits)
+		}
+	}
+
+	metrics := HealthMetrics{
+		Language: language,
+		Overall:  newConcentrationMetrics(overall),
+		Projects: make([]ProjectHealth, 0, len(projects)),
+	}
+	for _, p := range projects {
+		metrics.Projects = append(metrics.Projects, ProjectHealth{
+			Project: p,
+			Metrics: newConcentrationMetrics(perProject[p.ID]),
+		})
+	}
+
+	return &metrics, nil
+}
+
+// newConcentrationMetrics computes metrics from contributors' commit counts.
+func newConcentrationMetrics(commits []int) ConcentrationMetrics {
+	sorted := make([]int, 0, len(commits))
+	var total int
+	for _, c := range commits {
+		if c <= 0 {
+			continue
+		}
+		sorted = append(sorted, c)
+		total += c
+	}
+	if total == 0 {
+		return ConcentrationMetrics{}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+	m := ConcentrationMetrics{
+		Contributors: len(sorted),
+		Commits:      total,
+	}
+
+	var sum int
+	for i, c := range sorted {
+		sum += c
+		if m.BusFactor == 0 && 2*sum >= total {
+			m.BusFactor = i + 1
+		}
+		switch i + 1 {
+		case 1:
+			m.Top1Share = float64(sum) / float64(total)
+		case 5:
+			m.Top5Share = float64(sum) / float64(total)
+		case 10:
+			m.Top10Share = float64(sum) / float64(total)
+		}
+	}
+	if len(sorted) < 5 {
+		m.Top5Share = 1
+	}
+	if len(sorted) < 10 {
+		m.Top10Share = 1
+	}
+
+	// Gini coefficient for values in ascending order: sum((2i - n - 1) * x_i) / (n * sum(x)), i in <1..n>.
+	n := len(sorted)
+	var weighted float64
+	for i, c := range sorted {
+		ascIdx := n - i
+		weighted += float64(2*ascIdx-n-1) * float64(c)
+	}
+	m.Gini = weighted / (float64(n) * float64(total))
+
+	return m
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConcentrationMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		commits []int
+		want    ConcentrationMetrics
+	}{
+		{
+			name:    "no commits",
+			commits: []int{0, 0},
+			want:    ConcentrationMetrics{},
+		},
+		{
+			name:    "single contributor",
+			commits: []int{10},
+			want: ConcentrationMetrics{
+				Contributors: 1,
+				Commits:      10,
+				BusFactor:    1,
+				Gini:         0,
+				Top1Share:    1,
+				Top5Share:    1,
+				Top10Share:   1,
+			},
+		},
+		{
+			name:    "equal distribution",
+			commits: []int{2, 2, 2, 2},
+			want: ConcentrationMetrics{
+				Contributors: 4,
+				Commits:      8,
+				BusFactor:    2,
+				Gini:         0,
+				Top1Share:    0.25,
+				Top5Share:    1,
+				Top10Share:   1,
+			},
+		},
+		{
+			name:    "unequal distribution",
+			commits: []int{1, 3},
+			want: ConcentrationMetrics{
+				Contributors: 2,
+				Commits:      4,
+				BusFactor:    1,
+				Gini:         0.25,
+				Top1Share:    0.75,
+				Top5Share:    1,
+				Top10Share:   1,
+			},
+		},
+		{
+			name:    "many contributors",
+			commits: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 10, 0},
+			want: ConcentrationMetrics{
+				Contributors: 11,
+				Commits:      20,
+				BusFactor:    1,
+				Gini:         90.0 / 220,
+				Top1Share:    0.5,
+				Top5Share:    0.7,
+				Top10Share:   0.95,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newConcentrationMetrics(tt.commits)
+			assert.InDelta(t, tt.want.Gini, got.Gini, 1e-9)
+			got.Gini = tt.want.Gini
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AliasStore persists contributor aliases managed at runtime.
+type AliasStore interface {
+	// Aliases returns stored aliases, mapping alias login to login of the main identity.
+	Aliases() (map[string]string, error)
+	SaveAliases(aliases map[string]string) error
+}
+
+// IdentityResolver merges stats of contributors using multiple accounts into a single identity.
+// Aliases come from static mapping (usually loaded from file) and from mapping managed at runtime.
+// Logins are matched case insensitively.
+type IdentityResolver struct {
+	static map[string]string
+	store  AliasStore
+
+	m       sync.RWMutex
+	runtime map[string]string
+}
+
+// NewIdentityResolver creates new IdentityResolver instance.
+// static maps login of the main identity to logins of its aliases.
+// store is optional, if nil aliases managed at runtime are not persisted.
+// Stored aliases are validated like the ones set with SetAlias, so static mapping can't be changed into conflicting one.
+func NewIdentityResolver(static map[string][]string, store AliasStore) (*IdentityResolver, error) {
+	r := IdentityResolver{
+		static:  make(map[string]string),
+		store:   store,
+		runtime: make(map[string]string),
+	}
+	for login, aliases := range static {
+		for _, alias := range aliases {
+			if err := r.validateAlias(alias, login); err != nil {
+				return nil, err
+			}
+			r.static[strings.ToLower(alias)] = login
+		}
+	}
+
+	if store != nil {
+		aliases, err := store.Aliases()
+		if err != nil {
+			return nil, fmt.Errorf("reading stored aliases: %w", err)
+		}
+		for alias, login := range aliases {
+			if err := r.validateRuntimeAlias(alias, login); err != nil {
+				return nil, fmt.Errorf("invalid stored alias: %w", err)
+			}
+			r.runtime[strings.ToLower(alias)] = login
+		}
+	}
+
+	return &r, nil
+}
+
+// Aliases returns all aliases, mapping alias login (lowercased) to login of the main identity.
+func (r *IdentityResolver) Aliases() map[string]string {
+	if r == nil {
+		return nil
+	}
+
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	aliases := make(map[string]string, len(r.static)+len(r.runtime))
+	for alias, login := range r.static {
+		aliases[alias] = login
+	}
+	for alias, login := range r.runtime {
+		aliases[alias] = login
+	}
+
+	return aliases
+}
+
+// SetAlias makes `alias` an alias of contributor with given login.
+// Aliases can't be chained, main identity can't be an alias itself.
+func (r *IdentityResolver) SetAlias(alias string, login string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if err := r.validateRuntimeAlias(alias, login); err != nil {
+		return err
+	}
+	key := strings.ToLower(alias)
+
+	runtime := make(map[string]string, len(r.runtime)+1)
+	for k, v := range r.runtime {
+		runtime[k] = v
+	}
+	runtime[key] = login
+	if err := r.save(runtime); err != nil {
+		return err
+	}
+	r.runtime = runtime
+
+	return nil
+}
+
+// DeleteAlias removes alias managed at runtime. Aliases from static mapping can't be deleted.
+func (r *IdentityResolver) DeleteAlias(alias string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	key := strings.ToLower(alias)
+	if _, ok := r.static[key]; ok {
+		return InvalidRequestError(fmt.Sprintf("alias '%s' is defined in mapping file", alias))
+	}
+	if _, ok := r.runtime[key]; !ok {
+		return nil
+	}
+
+	runtime := make(map[string]string, len(r.runtime))
+	for k, v := range r.runtime {
+		if k != key {
+			runtime[k] = v
+		}
+	}
+	if err := r.save(runtime); err != nil {
+		return err
+	}
+	r.runtime = runtime
+
+	return nil
+}
+
+// resolve returns login of the main identity of given login.
+func (r *IdentityResolver) resolve(login string) string {
+	if r == nil {
+		return login
+	}
+
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	if main, ok := r.lookup(strings.ToLower(login)); ok {
+		return main
+	}
+	return login
+}
+
+// merge merges stats of aliases into stats of their main identities.
+// Merged stats contain main identity's login. If main identity has no stats itself, lowest of aliases' IDs is used.
+func (r *IdentityResolver) merge(stats []ContributorStats) []ContributorStats {
+	if r == nil {
+		return stats
+	}
+
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	if len(r.static) == 0 && len(r.runtime) == 0 {
+		return stats
+	}
+
+	merged := make([]ContributorStats, 0, len(stats))
+	indexes := make(map[string]int)
+	hasMain := make(map[string]bool)
+	for _, st := range stats {
+		login, isAlias := r.lookup(strings.ToLower(st.Contributor.Login))
+		if !isAlias {
+			login = st.Contributor.Login
+		}
+		key := strings.ToLower(login)
+
+		idx, ok := indexes[key]
+		if !ok {
+			el := st
+			if isAlias {
+				el.Contributor = Contributor{
+					ID:    st.Contributor.ID,
+					Login: login,
+				}
+				el.Aliases = []Contributor{st.Contributor}
+			}
+			hasMain[key] = !isAlias
+			indexes[key] = len(merged)
+			merged = append(merged, el)
+			continue
+		}
+
+		el := &merged[idx]
+		switch {
+		case !isAlias:
+			el.Contributor = st.Contributor
+			hasMain[key] = true
+		case !hasMain[key] && st.Contributor.ID < el.Contributor.ID:
+			el.Contributor.ID = st.Contributor.ID
+		}
+		if isAlias {
+			el.Aliases = append(el.Aliases, st.Contributor)
+		}
+		el.Commits += st.Commits
+		el.Additions += st.Additions
+		el.Deletions += st.Deletions
+		el.Projects = mergeProjectContributions(el.Projects, st.Projects)
+	}
+
+	for i := range merged {
+		aliases := merged[i].Aliases
+		sort.Slice(aliases, func(i, j int) bool {
+			return aliases[i].ID < aliases[j].ID
+		})
+	}
+
+	return merged
+}
+
+func (r *IdentityResolver) lookup(key string) (string, bool) {
+	if login, ok := r.runtime[key]; ok {
+		return login, true
+	}
+	login, ok := r.static[key]
+	return login, ok
+}
+
+// validateAlias checks if alias can be added without creating alias chains.
+func (r *IdentityResolver) validateAlias(alias string, login string) error {
+	if alias == "" || login == "" {
+		return InvalidRequestError("alias and login cannot be empty")
+	}
+	if strings.EqualFold(alias, login) {
+		return InvalidRequestError(fmt.Sprintf("login '%s' cannot be its own alias", login))
+	}
+	if main, ok := r.lookup(strings.ToLower(alias)); ok && !strings.EqualFold(main, login) {
+		return InvalidRequestError(fmt.Sprintf("'%s' is already an alias of '%s'", alias, main))
+	}
+	if main, ok := r.lookup(strings.ToLower(login)); ok {
+		return InvalidRequestError(fmt.Sprintf("'%s' is an alias of '%s'", login, main))
+	}
+	for a, main := range r.static {
+		if strings.EqualFold(main, alias) {
+			return InvalidRequestError(fmt.Sprintf("'%s' has aliases, including '%s'", alias, a))
+		}
+	}
+	for a, main := range r.runtime {
+		if strings.EqualFold(main, alias) {
+			return InvalidRequestError(fmt.Sprintf("'%s' has aliases, including '%s'", alias, a))
+		}
+	}
+
+	return nil
+}
+
+// validateRuntimeAlias checks if alias can be managed at runtime, aliases from static mapping can't be overridden.
+func (r *IdentityResolver) validateRuntimeAlias(alias string, login string) error {
+	if err := r.validateAlias(alias, login); err != nil {
+		return err
+	}
+	if _, ok := r.static[strings.ToLower(alias)]; ok {
+		return InvalidRequestError(fmt.Sprintf("alias '%s' is defined in mapping file", alias))
+	}
+
+	return nil
+}
+
+func (r *IdentityResolver) save(runtime map[string]string) error {
+	if r.store == nil {
+		return nil
+	}
+	if err := r.store.SaveAliases(runtime); err != nil {
+		return fmt.Errorf("saving aliases: %w", err)
+	}
+	return nil
+}
+
+// mergeProjectContributions merges contributions in the same projects and sorts them by commits.
+func mergeProjectContributions(a, b []ProjectContribution) []ProjectContribution {
+	merged := make([]ProjectContribution, 0, len(a)+len(b))
+	indexes := make(map[int]int)
+	for _, pc := range append(append([]ProjectContribution{}, a...), b...) {
+		if idx, ok := indexes[pc.Project.ID]; ok {
+			merged[idx].Commits += pc.Commits
+			continue
+		}
+		indexes[pc.Project.ID] = len(merged)
+		merged = append(merged, pc)
+	}
+	sortProjectContributions(merged)
+
+	return merged
+}
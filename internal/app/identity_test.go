@@ -0,0 +1,227 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdentityResolver(t *testing.T) {
+	tests := []struct {
+		name    string
+		static  map[string][]string
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			static: nil,
+		},
+		{
+			name: "valid",
+			static: map[string][]string{
+				"a": {"a-work", "a2"},
+				"b": {"b-work"},
+			},
+		},
+		{
+			name: "own alias",
+			static: map[string][]string{
+				"a": {"A"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "alias of two identities",
+			static: map[string][]string{
+				"a": {"x"},
+				"b": {"X"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "chained aliases",
+			static: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewIdentityResolver(tt.static, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err != nil {
+				assert.True(t, IsInvalidRequestError(err))
+			}
+		})
+	}
+}
+
+func TestNewIdentityResolverStoredAliases(t *testing.T) {
+	static := map[string][]string{"a": {"a-work"}}
+
+	tests := []struct {
+		name    string
+		stored  map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			stored: map[string]string{"b-work": "b"},
+		},
+		{
+			name:    "alias from mapping file",
+			stored:  map[string]string{"a-work": "b"},
+			wantErr: true,
+		},
+		{
+			name:    "alias of alias from mapping file",
+			stored:  map[string]string{"x": "a-work"},
+			wantErr: true,
+		},
+		{
+			name:    "main identity from mapping file as alias",
+			stored:  map[string]string{"a": "b"},
+			wantErr: true,
+		},
+		{
+			name:    "cycle",
+			stored:  map[string]string{"b": "c", "c": "b"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewIdentityResolver(static, &testAliasStore{aliases: tt.stored})
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err != nil {
+				assert.True(t, IsInvalidRequestError(err))
+			}
+		})
+	}
+}
+
+func TestIdentityResolverRuntimeAliases(t *testing.T) {
+	store := &testAliasStore{
+		aliases: map[string]string{
+			"stored": "b",
+		},
+	}
+	r, err := NewIdentityResolver(map[string][]string{"a": {"a-work"}}, store)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a-work": "a", "stored": "b"}, r.Aliases())
+
+	assert.NoError(t, r.SetAlias("B-Work", "b"))
+	assert.Equal(t, "b", r.resolve("b-work"))
+	assert.Equal(t, map[string]string{"stored": "b", "b-work": "b"}, store.aliases)
+
+	// Invalid aliases.
+	assert.True(t, IsInvalidRequestError(r.SetAlias("a-work", "b")))
+	assert.True(t, IsInvalidRequestError(r.SetAlias("x", "b-work")))
+	assert.True(t, IsInvalidRequestError(r.SetAlias("b", "c")))
+	assert.True(t, IsInvalidRequestError(r.SetAlias("", "c")))
+	assert.True(t, IsInvalidRequestError(r.DeleteAlias("a-work")))
+
+	assert.NoError(t, r.DeleteAlias("stored"))
+	assert.NoError(t, r.DeleteAlias("unknown"))
+	assert.Equal(t, "stored", r.resolve("stored"))
+	assert.Equal(t, map[string]string{"b-work": "b"}, store.aliases)
+
+	// Store failure doesn't change aliases.
+	store.err = errors.New("error")
+	assert.Error(t, r.SetAlias("c-work", "c"))
+	assert.Equal(t, "c-work", r.resolve("c-work"))
+}
+
+func TestIdentityResolverMerge(t *testing.T) {
+	project := func(id int) Project {
+		return Project{ID: id}
+	}
+	r, err := NewIdentityResolver(
+		map[string][]string{
+			"a": {"a-work", "a-old"},
+			"b": {"b-work"},
+		},
+		nil,
+	)
+	require.NoError(t, err)
+
+	stats := []ContributorStats{
+		{
+			Contributor: Contributor{ID: 5, Login: "a-work"},
+			Commits:     1,
+			Additions:   10,
+			Projects:    []ProjectContribution{{Project: project(1), Commits: 1}},
+		},
+		{
+			Contributor: Contributor{ID: 1, Login: "A"},
+			Commits:     2,
+			Deletions:   20,
+			Projects:    []ProjectContribution{{Project: project(1), Commits: 2}},
+		},
+		{
+			Contributor: Contributor{ID: 3, Login: "a-old"},
+			Commits:     4,
+			Projects:    []ProjectContribution{{Project: project(2), Commits: 4}},
+		},
+		{
+			Contributor: Contributor{ID: 7, Login: "b-work"},
+			Commits:     1,
+		},
+		{
+			Contributor: Contributor{ID: 8, Login: "c"},
+			Commits:     1,
+		},
+	}
+	want := []ContributorStats{
+		{
+			Contributor: Contributor{ID: 1, Login: "A"},
+			Commits:     7,
+			Additions:   10,
+			Deletions:   20,
+			Projects: []ProjectContribution{
+				{Project: project(2), Commits: 4},
+				{Project: project(1), Commits: 3},
+			},
+			Aliases: []Contributor{
+				{ID: 3, Login: "a-old"},
+				{ID: 5, Login: "a-work"},
+			},
+		},
+		{
+			Contributor: Contributor{ID: 7, Login: "b"},
+			Commits:     1,
+			Aliases: []Contributor{
+				{ID: 7, Login: "b-work"},
+			},
+		},
+		{
+			Contributor: Contributor{ID: 8, Login: "c"},
+			Commits:     1,
+		},
+	}
+	assert.Equal(t, want, r.merge(stats))
+
+	var nilResolver *IdentityResolver
+	assert.Equal(t, stats, nilResolver.merge(stats))
+}
+
+type testAliasStore struct {
+	aliases map[string]string
+	err     error
+}
+
+func (s *testAliasStore) Aliases() (map[string]string, error) {
+	return s.aliases, s.err
+}
+
+func (s *testAliasStore) SaveAliases(aliases map[string]string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.aliases = aliases
+	return nil
+}
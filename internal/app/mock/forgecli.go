@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/m-zajac/goprojectdemo/internal/app (interfaces: GithubClient)
+// Source: github.com/m-zajac/goprojectdemo/internal/app (interfaces: ForgeClient)
 
 // Package mock is a generated GoMock package.
 package mock
@@ -11,31 +11,31 @@ import (
 	reflect "reflect"
 )
 
-// MockGithubClient is a mock of GithubClient interface
-type MockGithubClient struct {
+// MockForgeClient is a mock of ForgeClient interface
+type MockForgeClient struct {
 	ctrl     *gomock.Controller
-	recorder *MockGithubClientMockRecorder
+	recorder *MockForgeClientMockRecorder
 }
 
-// MockGithubClientMockRecorder is the mock recorder for MockGithubClient
-type MockGithubClientMockRecorder struct {
-	mock *MockGithubClient
+// MockForgeClientMockRecorder is the mock recorder for MockForgeClient
+type MockForgeClientMockRecorder struct {
+	mock *MockForgeClient
 }
 
-// NewMockGithubClient creates a new mock instance
-func NewMockGithubClient(ctrl *gomock.Controller) *MockGithubClient {
-	mock := &MockGithubClient{ctrl: ctrl}
-	mock.recorder = &MockGithubClientMockRecorder{mock}
+// NewMockForgeClient creates a new mock instance
+func NewMockForgeClient(ctrl *gomock.Controller) *MockForgeClient {
+	mock := &MockForgeClient{ctrl: ctrl}
+	mock.recorder = &MockForgeClientMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockGithubClient) EXPECT() *MockGithubClientMockRecorder {
+func (m *MockForgeClient) EXPECT() *MockForgeClientMockRecorder {
 	return m.recorder
 }
 
 // ProjectsByLanguage mocks base method
-func (m *MockGithubClient) ProjectsByLanguage(arg0 context.Context, arg1 string, arg2 int) ([]app.Project, error) {
+func (m *MockForgeClient) ProjectsByLanguage(arg0 context.Context, arg1 app.ProjectsQuery, arg2 int) ([]app.Project, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ProjectsByLanguage", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]app.Project)
@@ -44,13 +44,13 @@ func (m *MockGithubClient) ProjectsByLanguage(arg0 context.Context, arg1 string,
 }
 
 // ProjectsByLanguage indicates an expected call of ProjectsByLanguage
-func (mr *MockGithubClientMockRecorder) ProjectsByLanguage(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockForgeClientMockRecorder) ProjectsByLanguage(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectsByLanguage", reflect.TypeOf((*MockGithubClient)(nil).ProjectsByLanguage), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectsByLanguage", reflect.TypeOf((*MockForgeClient)(nil).ProjectsByLanguage), arg0, arg1, arg2)
 }
 
 // StatsByProject mocks base method
-func (m *MockGithubClient) StatsByProject(arg0 context.Context, arg1, arg2 string) ([]app.ContributorStats, error) {
+func (m *MockForgeClient) StatsByProject(arg0 context.Context, arg1, arg2 string) ([]app.ContributorStats, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "StatsByProject", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]app.ContributorStats)
@@ -59,7 +59,7 @@ func (m *MockGithubClient) StatsByProject(arg0 context.Context, arg1, arg2 strin
 }
 
 // StatsByProject indicates an expected call of StatsByProject
-func (mr *MockGithubClientMockRecorder) StatsByProject(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockForgeClientMockRecorder) StatsByProject(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatsByProject", reflect.TypeOf((*MockGithubClient)(nil).StatsByProject), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatsByProject", reflect.TypeOf((*MockForgeClient)(nil).StatsByProject), arg0, arg1, arg2)
 }
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/m-zajac/goprojectdemo/internal/app (interfaces: SnapshotStore)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	app "github.com/m-zajac/goprojectdemo/internal/app"
+	reflect "reflect"
+	time "time"
+)
+
+// MockSnapshotStore is a mock of SnapshotStore interface
+type MockSnapshotStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotStoreMockRecorder
+}
+
+// MockSnapshotStoreMockRecorder is the mock recorder for MockSnapshotStore
+type MockSnapshotStoreMockRecorder struct {
+	mock *MockSnapshotStore
+}
+
+// NewMockSnapshotStore creates a new mock instance
+func NewMockSnapshotStore(ctrl *gomock.Controller) *MockSnapshotStore {
+	mock := &MockSnapshotStore{ctrl: ctrl}
+	mock.recorder = &MockSnapshotStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSnapshotStore) EXPECT() *MockSnapshotStoreMockRecorder {
+	return m.recorder
+}
+
+// SaveSnapshot mocks base method
+func (m *MockSnapshotStore) SaveSnapshot(arg0 app.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSnapshot", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSnapshot indicates an expected call of SaveSnapshot
+func (mr *MockSnapshotStoreMockRecorder) SaveSnapshot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).SaveSnapshot), arg0)
+}
+
+// Snapshot mocks base method
+func (m *MockSnapshotStore) Snapshot(arg0 string, arg1 time.Time) (*app.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", arg0, arg1)
+	ret0, _ := ret[0].(*app.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot
+func (mr *MockSnapshotStoreMockRecorder) Snapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockSnapshotStore)(nil).Snapshot), arg0, arg1)
+}
+
+// SnapshotTimes mocks base method
+func (m *MockSnapshotStore) SnapshotTimes(arg0 string) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotTimes", arg0)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotTimes indicates an expected call of SnapshotTimes
+func (mr *MockSnapshotStoreMockRecorder) SnapshotTimes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotTimes", reflect.TypeOf((*MockSnapshotStore)(nil).SnapshotTimes), arg0)
+}
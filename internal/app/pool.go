@@ -0,0 +1,199 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// errDeadlineApproaching is passed to dropped tasks when there's not enough time left to run them.
+var errDeadlineApproaching = fmt.Errorf("not enough time left to run task: %w", context.DeadlineExceeded)
+
+// WorkerPool runs tasks of all service requests with limited concurrency.
+// Each request queues its tasks in a separate queue. Workers take tasks from queues in round robin order,
+// so requests with many tasks don't starve other requests.
+// Queued tasks are dropped when request's context is done, or when its deadline is closer than `minTimeLeft`.
+type WorkerPool struct {
+	workers     int
+	minTimeLeft time.Duration
+
+	m       sync.Mutex
+	running int
+	// queues holds queues with pending tasks.
+	queues []*taskQueue
+	next   int
+}
+
+// NewWorkerPool creates new WorkerPool instance running at most `workers` tasks at once.
+func NewWorkerPool(workers int, minTimeLeft time.Duration) (*WorkerPool, error) {
+	if workers <= 0 {
+		return nil, errors.New("number of workers must be greater than 0")
+	}
+	if minTimeLeft < 0 {
+		return nil, errors.New("min time left cannot be negative")
+	}
+
+	return &WorkerPool{
+		workers:     workers,
+		minTimeLeft: minTimeLeft,
+	}, nil
+}
+
+type poolTask struct {
+	run func(ctx context.Context)
+	// drop is called instead of run when task is dropped.
+	drop func(err error)
+}
+
+// taskQueue holds tasks of a single request.
+type taskQueue struct {
+	pool     *WorkerPool
+	ctx      context.Context
+	startCtx context.Context
+	cancel   context.CancelFunc
+
+	tasks  []poolTask
+	active bool
+	closed bool
+}
+
+// newQueue creates queue for tasks of request with given context. Queue must be closed when no longer needed.
+// If pool is nil, each task runs in a separate goroutine right away.
+func (p *WorkerPool) newQueue(ctx context.Context) *taskQueue {
+	q := taskQueue{
+		pool: p,
+		ctx:  ctx,
+	}
+	if p == nil {
+		return &q
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		q.startCtx, q.cancel = context.WithDeadline(ctx, deadline.Add(-p.minTimeLeft))
+	} else {
+		q.startCtx, q.cancel = context.WithCancel(ctx)
+	}
+	go func() {
+		<-q.startCtx.Done()
+		p.dropQueued(&q)
+	}()
+
+	return &q
+}
+
+// submit queues task. Either run or drop function is called, unless queue is closed before.
+func (q *taskQueue) submit(run func(ctx context.Context), drop func(err error)) {
+	p := q.pool
+	if p == nil {
+		go run(q.ctx)
+		return
+	}
+
+	if q.startCtx.Err() != nil {
+		drop(q.dropErr())
+		return
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.tasks = append(q.tasks, poolTask{run: run, drop: drop})
+	if !q.active {
+		q.active = true
+		p.queues = append(p.queues, q)
+	}
+	if p.running < p.workers {
+		p.running++
+		go p.work()
+	}
+}
+
+// close discards tasks that haven't started yet.
+func (q *taskQueue) close() {
+	p := q.pool
+	if p == nil {
+		return
+	}
+
+	p.m.Lock()
+	q.closed = true
+	q.tasks = nil
+	p.m.Unlock()
+
+	q.cancel()
+}
+
+func (q *taskQueue) dropErr() error {
+	if err := q.ctx.Err(); err != nil {
+		return err
+	}
+	return errDeadlineApproaching
+}
+
+func (p *WorkerPool) work() {
+	for {
+		p.m.Lock()
+		q, t, ok := p.nextTask()
+		if !ok {
+			p.running--
+			p.m.Unlock()
+			return
+		}
+		p.m.Unlock()
+
+		if q.startCtx.Err() != nil {
+			t.drop(q.dropErr())
+			continue
+		}
+		t.run(q.ctx)
+	}
+}
+
+// nextTask takes task from the next queue in round robin order. Must be called with lock held.
+func (p *WorkerPool) nextTask() (*taskQueue, poolTask, bool) {
+	for len(p.queues) > 0 {
+		if p.next >= len(p.queues) {
+			p.next = 0
+		}
+		q := p.queues[p.next]
+		if len(q.tasks) == 0 {
+			p.removeQueue(p.next)
+			continue
+		}
+
+		t := q.tasks[0]
+		q.tasks = q.tasks[1:]
+		if len(q.tasks) == 0 {
+			p.removeQueue(p.next)
+		} else {
+			p.next++
+		}
+		return q, t, true
+	}
+
+	return nil, poolTask{}, false
+}
+
+// removeQueue removes queue at given index from queues with pending tasks. Must be called with lock held.
+func (p *WorkerPool) removeQueue(idx int) {
+	p.queues[idx].active = false
+	p.queues = append(p.queues[:idx], p.queues[idx+1:]...)
+}
+
+// dropQueued drops all queued tasks of given queue.
+func (p *WorkerPool) dropQueued(q *taskQueue) {
+	p.m.Lock()
+	tasks := q.tasks
+	q.tasks = nil
+	p.m.Unlock()
+
+	err := q.dropErr()
+	for _, t := range tasks {
+		t.drop(err)
+	}
+}
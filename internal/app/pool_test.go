@@ -0,0 +1,239 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWorkerPool(t *testing.T) {
+	_, err := NewWorkerPool(0, 0)
+	assert.Error(t, err)
+	_, err = NewWorkerPool(1, -time.Second)
+	assert.Error(t, err)
+	_, err = NewWorkerPool(1, time.Second)
+	assert.NoError(t, err)
+}
+
+func TestWorkerPoolConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	const workers = 3
+	const tasks = 20
+
+	pool, err := NewWorkerPool(workers, 0)
+	require.NoError(t, err)
+
+	var m sync.Mutex
+	var running, maxRunning int
+	var wg sync.WaitGroup
+	wg.Add(tasks)
+
+	queue := pool.newQueue(context.Background())
+	defer queue.close()
+	for i := 0; i < tasks; i++ {
+		queue.submit(
+			func(ctx context.Context) {
+				defer wg.Done()
+
+				m.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				m.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				m.Lock()
+				running--
+				m.Unlock()
+			},
+			func(err error) {
+				t.Errorf("unexpected drop: %v", err)
+				wg.Done()
+			},
+		)
+	}
+	wg.Wait()
+
+	assert.Equal(t, workers, maxRunning)
+}
+
+func TestWorkerPoolFairScheduling(t *testing.T) {
+	t.Parallel()
+
+	pool, err := NewWorkerPool(1, 0)
+	require.NoError(t, err)
+
+	var m sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	task := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			defer wg.Done()
+			m.Lock()
+			order = append(order, name)
+			m.Unlock()
+		}
+	}
+	drop := func(err error) {
+		t.Errorf("unexpected drop: %v", err)
+		wg.Done()
+	}
+
+	// Keep the only worker busy until all tasks are queued.
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blocking := pool.newQueue(context.Background())
+	defer blocking.close()
+	wg.Add(1)
+	blocking.submit(
+		func(ctx context.Context) {
+			defer wg.Done()
+			close(started)
+			<-release
+		},
+		drop,
+	)
+	<-started
+
+	a := pool.newQueue(context.Background())
+	defer a.close()
+	b := pool.newQueue(context.Background())
+	defer b.close()
+	wg.Add(5)
+	a.submit(task("a1"), drop)
+	a.submit(task("a2"), drop)
+	a.submit(task("a3"), drop)
+	b.submit(task("b1"), drop)
+	b.submit(task("b2"), drop)
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"a1", "b1", "a2", "b2", "a3"}, order)
+}
+
+func TestWorkerPoolDropsTasksNearDeadline(t *testing.T) {
+	t.Parallel()
+
+	pool, err := NewWorkerPool(1, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	dropped := make(chan error, 1)
+
+	queue := pool.newQueue(ctx)
+	defer queue.close()
+	queue.submit(
+		func(ctx context.Context) {
+			<-release
+		},
+		func(err error) {
+			t.Errorf("unexpected drop: %v", err)
+		},
+	)
+	queue.submit(
+		func(ctx context.Context) {
+			t.Error("task should be dropped")
+		},
+		func(err error) {
+			dropped <- err
+		},
+	)
+
+	select {
+	case err := <-dropped:
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.NoError(t, ctx.Err(), "task should be dropped before deadline")
+	case <-time.After(time.Second):
+		t.Fatal("task wasn't dropped")
+	}
+
+	queue.submit(
+		func(ctx context.Context) {
+			t.Error("task should be dropped")
+		},
+		func(err error) {
+			dropped <- err
+		},
+	)
+	assert.True(t, errors.Is(<-dropped, context.DeadlineExceeded))
+}
+
+func TestWorkerPoolDropsTasksOfCanceledRequest(t *testing.T) {
+	t.Parallel()
+
+	pool, err := NewWorkerPool(1, 0)
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	blocking := pool.newQueue(context.Background())
+	defer blocking.close()
+	blocking.submit(
+		func(ctx context.Context) {
+			close(started)
+			<-release
+		},
+		func(err error) {
+			t.Errorf("unexpected drop: %v", err)
+		},
+	)
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dropped := make(chan error, 1)
+	queue := pool.newQueue(ctx)
+	defer queue.close()
+	queue.submit(
+		func(ctx context.Context) {
+			t.Error("task should be dropped")
+		},
+		func(err error) {
+			dropped <- err
+		},
+	)
+	cancel()
+
+	select {
+	case err := <-dropped:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Fatal("task wasn't dropped")
+	}
+}
+
+func TestNilWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	var pool *WorkerPool
+	done := make(chan struct{})
+	queue := pool.newQueue(context.Background())
+	defer queue.close()
+	queue.submit(
+		func(ctx context.Context) {
+			close(done)
+		},
+		func(err error) {
+			t.Errorf("unexpected drop: %v", err)
+		},
+	)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task didn't run")
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProjectFilter narrows down searched projects. Zero value means no additional filtering.
+type ProjectFilter struct {
+	// MinStars is the minimum number of project's stars.
+	MinStars int
+	// ExcludeForks excludes forked projects.
+	ExcludeForks bool
+	// ExcludeArchived excludes archived projects.
+	ExcludeArchived bool
+	// Topics are required project's topics. Project must have all of them.
+	Topics []string
+	// PushedAfter excludes projects without pushes since given time.
+	PushedAfter time.Time
+	// License is the required license keyword, e.g. "mit".
+	License string
+}
+
+// IsZero tells if filter doesn't narrow down projects.
+func (f ProjectFilter) IsZero() bool {
+	return f.MinStars == 0 &&
+		!f.ExcludeForks &&
+		!f.ExcludeArchived &&
+		len(f.Topics) == 0 &&
+		f.PushedAfter.IsZero() &&
+		f.License == ""
+}
+
+func (f ProjectFilter) validate() error {
+	if f.MinStars < 0 {
+		return InvalidRequestError("min stars cannot be negative")
+	}
+	for _, t := range f.Topics {
+		if t == "" || strings.ContainsAny(t, " :") {
+			return InvalidRequestError(fmt.Sprintf("invalid topic '%s'", t))
+		}
+	}
+	if strings.ContainsAny(f.License, " :") {
+		return InvalidRequestError(fmt.Sprintf("invalid license '%s'", f.License))
+	}
+	return nil
+}
+
+// ProjectsQuery describes projects returned by ForgeClient.
+type ProjectsQuery struct {
+	// Forge hosting the projects. Empty means DefaultForge.
+	Forge    string
+	Language string
+	Filter   ProjectFilter
+}
+
+// Key returns canonical representation of the query. Equal queries have equal keys.
+// Key of a query without filters is the language name.
+// Forge is not a part of the key, each forge has its own client.
+func (q ProjectsQuery) Key() string {
+	if q.Filter.IsZero() {
+		return q.Language
+	}
+
+	f := q.Filter
+	// Topics are escaped, so their separator can't be a part of a topic.
+	topics := make([]string, len(f.Topics))
+	for i, t := range f.Topics {
+		topics[i] = url.QueryEscape(t)
+	}
+	sort.Strings(topics)
+
+	var pushedAfter string
+	if !f.PushedAfter.IsZero() {
+		pushedAfter = f.PushedAfter.UTC().Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf(
+		"%s?stars=%d&forks=%t&archived=%t&topics=%s&pushed=%s&license=%s",
+		q.Language,
+		f.MinStars,
+		!f.ExcludeForks,
+		!f.ExcludeArchived,
+		strings.Join(topics, ","),
+		pushedAfter,
+		url.QueryEscape(strings.ToLower(f.License)),
+	)
+}
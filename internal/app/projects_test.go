@@ -0,0 +1,50 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectsQueryKey(t *testing.T) {
+	assert.Equal(t, "go", ProjectsQuery{Language: "go"}.Key())
+
+	q1 := ProjectsQuery{
+		Language: "go",
+		Filter: ProjectFilter{
+			MinStars:     10,
+			ExcludeForks: true,
+			Topics:       []string{"b", "a"},
+			PushedAfter:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			License:      "MIT",
+		},
+	}
+	q2 := ProjectsQuery{
+		Language: "go",
+		Filter: ProjectFilter{
+			MinStars:     10,
+			ExcludeForks: true,
+			Topics:       []string{"a", "b"},
+			PushedAfter:  time.Date(2020, 1, 1, 1, 0, 0, 0, time.FixedZone("x", 3600)),
+			License:      "mit",
+		},
+	}
+	assert.Equal(t, q1.Key(), q2.Key())
+	assert.Equal(t, []string{"b", "a"}, q1.Filter.Topics, "key must not modify query")
+
+	q2.Filter.ExcludeArchived = true
+	assert.NotEqual(t, q1.Key(), q2.Key())
+
+	q3 := ProjectsQuery{Language: "go", Filter: ProjectFilter{Topics: []string{"a,b"}}}
+	q4 := ProjectsQuery{Language: "go", Filter: ProjectFilter{Topics: []string{"a", "b"}}}
+	assert.NotEqual(t, q3.Key(), q4.Key())
+}
+
+func TestProjectFilterValidate(t *testing.T) {
+	assert.NoError(t, ProjectFilter{}.validate())
+	assert.NoError(t, ProjectFilter{MinStars: 1, Topics: []string{"cli"}, License: "mit"}.validate())
+	assert.True(t, IsInvalidRequestError(ProjectFilter{MinStars: -1}.validate()))
+	assert.True(t, IsInvalidRequestError(ProjectFilter{Topics: []string{"a b"}}.validate()))
+	assert.True(t, IsInvalidRequestError(ProjectFilter{License: "mit stars:>1"}.validate()))
+}
@@ -0,0 +1,89 @@
+package app
+
+import "fmt"
+
+// RankingMode tells which contributors stats value is used for ranking.
+type RankingMode string
+
+// Available ranking modes.
+const (
+	RankByCommits   RankingMode = "commits"
+	RankByAdditions RankingMode = "additions"
+	RankByDeletions RankingMode = "deletions"
+	RankByNetLines  RankingMode = "net"
+	RankByScore     RankingMode = "score"
+)
+
+// DefaultScoreWeights are used by RankByScore mode when no weights are given.
+var DefaultScoreWeights = ScoreWeights{
+	Commits:   1,
+	Additions: 0.01,
+	Deletions: 0.01,
+}
+
+// ScoreWeights are multipliers used to compute weighted score of contributor stats.
+type ScoreWeights struct {
+	Commits   float64
+	Additions float64
+	Deletions float64
+}
+
+// Ranking defines how contributors are ordered.
+type Ranking struct {
+	Mode RankingMode
+	// Weights are used only in RankByScore mode. If all weights are zero, DefaultScoreWeights are used.
+	Weights ScoreWeights
+}
+
+// ParseRankingMode returns ranking mode with given name. Empty name means RankByCommits.
+func ParseRankingMode(name string) (RankingMode, error) {
+	switch mode := RankingMode(name); mode {
+	case "":
+		return RankByCommits, nil
+	case RankByCommits, RankByAdditions, RankByDeletions, RankByNetLines, RankByScore:
+		return mode, nil
+	default:
+		return "", InvalidRequestError(fmt.Sprintf("invalid ranking mode '%s'", name))
+	}
+}
+
+// Value returns value of given stats used for ordering. Greater value means better rank.
+func (r Ranking) Value(s ContributorStats) float64 {
+	switch r.Mode {
+	case RankByAdditions:
+		return float64(s.Additions)
+	case RankByDeletions:
+		return float64(s.Deletions)
+	case RankByNetLines:
+		return float64(s.Additions - s.Deletions)
+	case RankByScore:
+		w := r.Weights
+		if w == (ScoreWeights{}) {
+			w = DefaultScoreWeights
+		}
+		return w.Commits*float64(s.Commits) + w.Additions*float64(s.Additions) + w.Deletions*float64(s.Deletions)
+	default:
+		return float64(s.Commits)
+	}
+}
+
+// usesLineStats tells if ranking takes added or deleted lines into account.
+func (r Ranking) usesLineStats() bool {
+	switch r.Mode {
+	case RankByAdditions, RankByDeletions, RankByNetLines:
+		return true
+	case RankByScore:
+		w := r.Weights
+		if w == (ScoreWeights{}) {
+			w = DefaultScoreWeights
+		}
+		return w.Additions != 0 || w.Deletions != 0
+	default:
+		return false
+	}
+}
+
+func (r Ranking) validate() error {
+	_, err := ParseRankingMode(string(r.Mode))
+	return err
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRankingMode(t *testing.T) {
+	mode, err := ParseRankingMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, RankByCommits, mode)
+
+	mode, err = ParseRankingMode("net")
+	assert.NoError(t, err)
+	assert.Equal(t, RankByNetLines, mode)
+
+	_, err = ParseRankingMode("stars")
+	assert.True(t, IsInvalidRequestError(err))
+}
+
+func TestRankingValue(t *testing.T) {
+	stats := ContributorStats{
+		Commits:   10,
+		Additions: 300,
+		Deletions: 100,
+	}
+
+	tests := []struct {
+		name    string
+		ranking Ranking
+		want    float64
+	}{
+		{
+			name:    "default",
+			ranking: Ranking{},
+			want:    10,
+		},
+		{
+			name:    "additions",
+			ranking: Ranking{Mode: RankByAdditions},
+			want:    300,
+		},
+		{
+			name:    "deletions",
+			ranking: Ranking{Mode: RankByDeletions},
+			want:    100,
+		},
+		{
+			name:    "net lines",
+			ranking: Ranking{Mode: RankByNetLines},
+			want:    200,
+		},
+		{
+			name:    "score with default weights",
+			ranking: Ranking{Mode: RankByScore},
+			want:    14,
+		},
+		{
+			name: "score with custom weights",
+			ranking: Ranking{
+				Mode: RankByScore,
+				Weights: ScoreWeights{
+					Commits:   2,
+					Deletions: 0.5,
+				},
+			},
+			want: 70,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.ranking.Value(stats))
+		})
+	}
+}
@@ -2,75 +2,343 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"expvar"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
+
+	"github.com/m-zajac/goprojectdemo/internal/singleflight"
 )
 
-// GithubClient returns details about gihub projects and stats.
-//go:generate mockgen -destination mock/githubcli.go -package mock github.com/m-zajac/goprojectdemo/internal/app GithubClient
-type GithubClient interface {
-	ProjectsByLanguage(ctx context.Context, language string, count int) ([]Project, error)
+// ForgeClient returns details about projects and stats hosted on a forge, like github, gitlab or gitea.
+//go:generate mockgen -destination mock/forgecli.go -package mock github.com/m-zajac/goprojectdemo/internal/app ForgeClient
+type ForgeClient interface {
+	ProjectsByLanguage(ctx context.Context, q ProjectsQuery, count int) ([]Project, error)
 	StatsByProject(ctx context.Context, name string, owner string) ([]ContributorStats, error)
 }
 
+// Supported forges.
+const (
+	ForgeGithub = "github"
+	ForgeGitlab = "gitlab"
+	// ForgeGitea is also used for Forgejo, which has the same api.
+	ForgeGitea = "gitea"
+
+	// DefaultForge is used by queries without forge.
+	DefaultForge = ForgeGithub
+)
+
+// forgesWithoutWeeklyStats lists forges which stats hold only all-time totals, without weekly activity.
+// Contributions on these forges can't be limited to a time window.
+var forgesWithoutWeeklyStats = map[string]bool{
+	ForgeGitlab: true,
+}
+
+// forgesWithoutLineStats lists forges which stats hold no added and deleted lines.
+// Contributors on these forges can't be ranked by lines.
+var forgesWithoutLineStats = map[string]bool{
+	ForgeGitea: true,
+}
+
+// Forges maps forge name to its client.
+type Forges map[string]ForgeClient
+
 // Service is main apps entry point. Provides all app functionality.
 type Service struct {
-	githubClient   GithubClient
+	forges         Forges
+	loginFilter    *LoginFilter
+	identities     *IdentityResolver
+	pool           *WorkerPool
 	requestTimeout time.Duration
+	degradedMargin time.Duration
+
+	// Group coalescing identical concurrent ranked stats computations.
+	flights singleflight.Group
 }
 
+// coalescingStats counts executed and shared ranked stats computations. Published under /debug/vars of the profiler server.
+var coalescingStats = expvar.NewMap("serviceCoalescing")
+
 // NewService creates new Service instance.
-func NewService(githubClient GithubClient, requestTimeout time.Duration) *Service {
+// forges holds clients of available forges, queries without forge use DefaultForge.
+// loginFilter is optional, if nil only logins matching per-query exclude patterns are filtered out.
+// identities is optional, if nil contributors' stats are not merged.
+// pool is optional, if nil projects' stats are requested without concurrency limit.
+//
+// If degradedMargin is greater than zero, service works in degraded mode: projects' stats are gathered
+// until `degradedMargin` before deadline. Stats not retrieved by then are skipped, and results computed
+// from the remaining ones are returned as partial instead of failing with timeout.
+func NewService(
+	forges Forges,
+	loginFilter *LoginFilter,
+	identities *IdentityResolver,
+	pool *WorkerPool,
+	requestTimeout time.Duration,
+	degradedMargin time.Duration,
+) *Service {
 	return &Service{
-		githubClient:   githubClient,
+		forges:         forges,
+		loginFilter:    loginFilter,
+		identities:     identities,
+		pool:           pool,
 		requestTimeout: requestTimeout,
+		degradedMargin: degradedMargin,
+	}
+}
+
+// MostActiveContributors returns most active contributors.
+// Contributions are taken from top `q.ProjectsCount` projects by the number of stars.
+// Returns top `q.Count` most active contributors, ordered by `q.Ranking` (commit count by default).
+//
+// In best-effort mode projects which stats couldn't be retrieved are skipped and listed in the result.
+// In degraded mode the same applies to projects which stats weren't retrieved before deadline.
+// Error is returned only if no project stats were retrieved.
+func (s *Service) MostActiveContributors(ctx context.Context, q ContributorsQuery) (*ContributorsResult, error) {
+	if q.Count <= 0 {
+		return nil, errors.New("count must be greater than zero")
 	}
+	if err := q.Ranking.validate(); err != nil {
+		return nil, err
+	}
+	if err := q.Window.validate(); err != nil {
+		return nil, err
+	}
+	if !q.Window.IsZero() && forgesWithoutWeeklyStats[q.Forge] {
+		return nil, InvalidRequestError(fmt.Sprintf("time window is not supported by forge '%s'", q.Forge))
+	}
+	if q.Ranking.usesLineStats() && forgesWithoutLineStats[q.Forge] {
+		return nil, InvalidRequestError(fmt.Sprintf("ranking by lines is not supported by forge '%s'", q.Forge))
+	}
+	if err := q.ProjectFilter.validate(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.rankedStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Stats) > q.Count {
+		result.Stats = result.Stats[:q.Count]
+	}
+
+	return result, nil
 }
 
-// MostActiveContributors returns contributions with most commits.
-// Contributions are taken from top `projectsCount` by the number of stars.
-// Returns top `count` most active contributors by commit count.
-func (s *Service) MostActiveContributors(
+// ContributorProfile returns activity of contributor with given login in top `projectsCount` projects
+// by the number of stars. Contributor's rank is computed by commit count.
+// If login is an alias, profile of the main identity is returned.
+// If contributor has no commits in these projects, NotFoundError is returned.
+// Empty forge means DefaultForge.
+func (s *Service) ContributorProfile(
 	ctx context.Context,
+	forge string,
+	login string,
 	language string,
 	projectsCount int,
-	count int,
-) ([]ContributorStats, error) {
-	if count <= 0 {
-		return nil, errors.New("count must be greater than zero")
+) (*ContributorProfile, error) {
+	if login == "" {
+		return nil, InvalidRequestError("login cannot be empty")
+	}
+
+	result, err := s.rankedStats(ctx, ContributorsQuery{
+		Forge:         forge,
+		Language:      language,
+		ProjectsCount: projectsCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats := result.Stats
+	login = s.identities.resolve(login)
+
+	for i, st := range stats {
+		// Github logins are case insensitive.
+		if !strings.EqualFold(st.Contributor.Login, login) {
+			continue
+		}
+
+		return &ContributorProfile{
+			Contributor:       st.Contributor,
+			Language:          language,
+			Rank:              i + 1,
+			ContributorsCount: len(stats),
+			Commits:           st.Commits,
+			Additions:         st.Additions,
+			Deletions:         st.Deletions,
+			Projects:          st.Projects,
+			Aliases:           st.Aliases,
+		}, nil
+	}
+
+	return nil, NotFoundError(fmt.Sprintf("contributor '%s' has no commits in top %s projects", login, language))
+}
+
+// TopProjects returns top `count` projects by the number of stars matching given query.
+// These are the projects contributors leaderboard for the same query is computed from.
+func (s *Service) TopProjects(ctx context.Context, q ProjectsQuery, count int) ([]Project, error) {
+	if err := q.Filter.validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := s.forge(q.Forge)
+	if err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, s.requestTimeout)
 	defer cancel()
 
-	projects, err := s.githubClient.ProjectsByLanguage(ctx, language, projectsCount)
+	projects, err := client.ProjectsByLanguage(ctx, q, count)
 	if err != nil {
-		return nil, fmt.Errorf("retrieving projects for language '%s': %w", language, err)
+		return nil, fmt.Errorf("retrieving projects for language '%s': %w", q.Language, err)
+	}
+
+	return projects, nil
+}
+
+// rankedStats returns stats of all contributors of query's projects, ordered by query's ranking.
+//
+// Identical concurrent computations are coalesced, returned result shares stats with other callers.
+// Computation is limited by service's request timeout or ctx deadline, whichever comes first,
+// and is canceled only if all callers waiting for it are gone.
+func (s *Service) rankedStats(ctx context.Context, q ContributorsQuery) (*ContributorsResult, error) {
+	deadline := time.Now().Add(s.requestTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
 	}
 
-	stats, err := s.gatherStats(ctx, projects)
+	v, shared, err := s.flights.Do(ctx, rankedStatsKey(q), func(ctx context.Context) (interface{}, error) {
+		ctx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+
+		return s.computeRankedStats(ctx, q)
+	})
+	if shared {
+		coalescingStats.Add("rankedStatsShared", 1)
+	} else {
+		coalescingStats.Add("rankedStatsExecuted", 1)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	result := *v.(*ContributorsResult)
+	return &result, nil
+}
+
+// rankedStatsKey returns key identifying ranked stats computed for given query.
+// Key is json encoded, so lists with different elements can't produce the same key.
+// Window bounds are truncated to seconds, so queries for last N weeks issued at the same time are identical.
+func rankedStatsKey(q ContributorsQuery) string {
+	forge := q.Forge
+	if forge == "" {
+		forge = DefaultForge
+	}
+	filter := q.ProjectFilter
+	filter.Topics = make([]string, len(q.ProjectFilter.Topics))
+	copy(filter.Topics, q.ProjectFilter.Topics)
+	sort.Strings(filter.Topics)
+	filter.PushedAfter = filter.PushedAfter.UTC()
+	filter.License = strings.ToLower(filter.License)
+
+	key, _ := json.Marshal(struct {
+		Forge         string
+		Language      string
+		Filter        ProjectFilter
+		ProjectsCount int
+		Ranking       Ranking
+		Since         int64
+		Until         int64
+		BestEffort    bool
+		Exclude       []string
+	}{
+		Forge:         forge,
+		Language:      q.Language,
+		Filter:        filter,
+		ProjectsCount: q.ProjectsCount,
+		Ranking:       q.Ranking,
+		Since:         q.Window.Since.Unix(),
+		Until:         q.Window.Until.Unix(),
+		BestEffort:    q.BestEffort,
+		Exclude:       q.Exclude,
+	})
+
+	return string(key)
+}
+
+func (s *Service) computeRankedStats(ctx context.Context, q ContributorsQuery) (*ContributorsResult, error) {
+	_, result, err := s.contributorsStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := result.Stats
 	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].Commits > stats[j].Commits
+		vi, vj := q.Ranking.Value(stats[i]), q.Ranking.Value(stats[j])
+		if vi != vj {
+			return vi > vj
+		}
+		return stats[i].Contributor.ID < stats[j].Contributor.ID
 	})
-	if len(stats) > count {
-		stats = stats[:count]
+
+	return result, nil
+}
+
+// contributorsStats returns query's projects and unordered stats of all their contributors.
+// Stats of contributors' aliases are merged.
+func (s *Service) contributorsStats(ctx context.Context, q ContributorsQuery) ([]Project, *ContributorsResult, error) {
+	client, err := s.forge(q.Forge)
+	if err != nil {
+		return nil, nil, err
+	}
+	exclude, err := compileLoginPatterns(q.Exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	projects, err := client.ProjectsByLanguage(
+		ctx,
+		ProjectsQuery{
+			Forge:    q.Forge,
+			Language: q.Language,
+			Filter:   q.ProjectFilter,
+		},
+		q.ProjectsCount,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("retrieving projects for language '%s': %w", q.Language, err)
 	}
 
-	return stats, nil
+	result, err := s.gatherStats(ctx, client, projects, q, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Stats = s.identities.merge(result.Stats)
+
+	return projects, result, nil
 }
 
-// gatherStats calls client for stats for each project in parallel.
-// Returns aggregated results.
-func (s *Service) gatherStats(ctx context.Context, projects []Project) ([]ContributorStats, error) {
+// gatherStats calls client for stats for each project in parallel, using service's worker pool.
+// Projects which stats requests were dropped by the pool are treated as timed out.
+// Returns aggregated results, counting only contributions within query's time window.
+// Aggregated stats contain per-project breakdown ordered by commits, but don't contain weekly activity.
+//
+// Contributors excluded by service's login filter or given patterns are counted, but not included in stats.
+//
+// In best-effort mode failed projects are returned as skipped, unless all of them failed.
+// In degraded mode the same applies to projects which stats weren't retrieved in time.
+func (s *Service) gatherStats(
+	ctx context.Context,
+	client ForgeClient,
+	projects []Project,
+	q ContributorsQuery,
+	exclude []loginPattern,
+) (*ContributorsResult, error) {
 	type respWrapper struct {
-		owner string
-		name  string
+		idx   int
 		stats []ContributorStats
 		err   error
 	}
@@ -78,27 +346,91 @@ func (s *Service) gatherStats(ctx context.Context, projects []Project) ([]Contri
 	responses := make(chan respWrapper, len(projects))
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	for _, p := range projects {
-		p := p
-		go func() {
-			stats, err := s.githubClient.StatsByProject(ctx, p.Name, p.OwnerLogin)
-			responses <- respWrapper{
-				owner: p.OwnerLogin,
-				name:  p.Name,
-				stats: stats,
-				err:   err,
-			}
-		}()
+
+	// In degraded mode stop waiting for responses a bit before deadline, so there's time to return partial results.
+	waitCtx := ctx
+	deadline, degraded := ctx.Deadline()
+	degraded = degraded && s.degradedMargin > 0
+	if degraded {
+		var cancelWait context.CancelFunc
+		waitCtx, cancelWait = context.WithDeadline(ctx, deadline.Add(-s.degradedMargin))
+		defer cancelWait()
 	}
 
+	queue := s.pool.newQueue(ctx)
+	defer queue.close()
+	for i, p := range projects {
+		i, p := i, p
+		queue.submit(
+			func(ctx context.Context) {
+				stats, err := client.StatsByProject(ctx, p.Name, p.OwnerLogin)
+				responses <- respWrapper{
+					idx:   i,
+					stats: stats,
+					err:   err,
+				}
+			},
+			func(err error) {
+				responses <- respWrapper{
+					idx: i,
+					err: err,
+				}
+			},
+		)
+	}
+
+	received := make([]bool, len(projects))
+	failures := make(map[int]SkippedProject)
 	statsMap := make(map[int]ContributorStats)
+	filtered := make(map[int]bool)
+loop:
 	for i := 0; i < cap(responses); i++ {
-		resp := <-responses
+		var resp respWrapper
+		select {
+		case resp = <-responses:
+		case <-waitCtx.Done():
+			if !q.BestEffort && !degraded {
+				return nil, fmt.Errorf("waiting for projects stats: %w", waitCtx.Err())
+			}
+			for idx, ok := range received {
+				if !ok {
+					failures[idx] = SkippedProject{
+						Project: projects[idx],
+						Reason:  SkipReasonTimeout,
+						Err:     waitCtx.Err(),
+					}
+				}
+			}
+			break loop
+		}
+		received[resp.idx] = true
+
 		if resp.err != nil {
-			return nil, fmt.Errorf("retrievieng project %s/%s stats: %w", resp.owner, resp.name, resp.err)
+			p := projects[resp.idx]
+			err := fmt.Errorf("retrievieng project %s/%s stats: %w", p.OwnerLogin, p.Name, resp.err)
+			reason := skipReason(resp.err)
+			if !q.BestEffort && !(degraded && reason == SkipReasonTimeout) {
+				return nil, err
+			}
+			failures[resp.idx] = SkippedProject{
+				Project: p,
+				Reason:  reason,
+				Err:     err,
+			}
+			continue
 		}
 
 		for _, stat := range resp.stats {
+			if s.loginFilter.excluded(stat.Contributor.Login, exclude) {
+				filtered[stat.Contributor.ID] = true
+				continue
+			}
+
+			stat = q.Window.Apply(stat)
+			if stat.Commits == 0 && !q.Window.IsZero() {
+				continue
+			}
+
 			el, ok := statsMap[stat.Contributor.ID]
 			if !ok {
 				el = ContributorStats{
@@ -109,14 +441,78 @@ func (s *Service) gatherStats(ctx context.Context, projects []Project) ([]Contri
 				}
 			}
 			el.Commits += stat.Commits
+			el.Additions += stat.Additions
+			el.Deletions += stat.Deletions
+			el.Projects = append(el.Projects, ProjectContribution{
+				Project: projects[resp.idx],
+				Commits: stat.Commits,
+			})
 			statsMap[stat.Contributor.ID] = el
 		}
 	}
 
-	result := make([]ContributorStats, 0, len(statsMap))
+	var skipped []SkippedProject
+	for idx := range projects {
+		if f, ok := failures[idx]; ok {
+			skipped = append(skipped, f)
+		}
+	}
+	if len(projects) > 0 && len(skipped) == len(projects) {
+		return nil, skipped[0].Err
+	}
+
+	stats := make([]ContributorStats, 0, len(statsMap))
 	for _, el := range statsMap {
-		result = append(result, el)
+		sortProjectContributions(el.Projects)
+		stats = append(stats, el)
 	}
 
-	return result, nil
+	completeness := 1.0
+	if len(projects) > 0 {
+		completeness = float64(len(projects)-len(skipped)) / float64(len(projects))
+	}
+
+	return &ContributorsResult{
+		Stats:        stats,
+		Skipped:      skipped,
+		Filtered:     len(filtered),
+		Completeness: completeness,
+	}, nil
+}
+
+// forge returns client of forge with given name. Empty name means DefaultForge.
+func (s *Service) forge(name string) (ForgeClient, error) {
+	if name == "" {
+		name = DefaultForge
+	}
+	client, ok := s.forges[name]
+	if !ok {
+		return nil, InvalidRequestError(fmt.Sprintf("unsupported forge '%s'", name))
+	}
+
+	return client, nil
+}
+
+// sortProjectContributions sorts contributions by commits in descending order.
+func sortProjectContributions(ps []ProjectContribution) {
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Commits != ps[j].Commits {
+			return ps[i].Commits > ps[j].Commits
+		}
+		return ps[i].Project.ID < ps[j].Project.ID
+	})
+}
+
+// skipReason returns reason for skipping project which stats retrieval failed with given error.
+func skipReason(err error) SkipReason {
+	switch {
+	case IsScheduledForLaterError(err):
+		return SkipReasonScheduled
+	case IsNotFoundError(err):
+		return SkipReasonNotFound
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return SkipReasonTimeout
+	default:
+		return SkipReasonError
+	}
 }
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankedStatsKey(t *testing.T) {
+	now := time.Now()
+	q := ContributorsQuery{
+		Language:      "go",
+		ProjectsCount: 10,
+		Window:        TimeWindow{Since: now.Add(-time.Hour), Until: now},
+		ProjectFilter: ProjectFilter{
+			Topics:  []string{"b", "a"},
+			License: "MIT",
+		},
+	}
+
+	same := q
+	same.Forge = DefaultForge
+	same.Count = 5
+	same.ProjectFilter.Topics = []string{"a", "b"}
+	same.ProjectFilter.License = "mit"
+	assert.Equal(t, rankedStatsKey(q), rankedStatsKey(same))
+
+	tests := []struct {
+		name   string
+		q1, q2 ContributorsQuery
+	}{
+		{
+			name: "exclude patterns",
+			q1:   ContributorsQuery{Language: "go", Exclude: []string{"a,b"}},
+			q2:   ContributorsQuery{Language: "go", Exclude: []string{"a", "b"}},
+		},
+		{
+			name: "topics",
+			q1:   ContributorsQuery{Language: "go", ProjectFilter: ProjectFilter{Topics: []string{"a,b"}}},
+			q2:   ContributorsQuery{Language: "go", ProjectFilter: ProjectFilter{Topics: []string{"a", "b"}}},
+		},
+		{
+			name: "forge",
+			q1:   ContributorsQuery{Language: "go"},
+			q2:   ContributorsQuery{Language: "go", Forge: ForgeGitlab},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotEqual(t, rankedStatsKey(tt.q1), rankedStatsKey(tt.q2))
+		})
+	}
+}
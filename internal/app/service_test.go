@@ -10,23 +10,33 @@ import (
 	"github.com/m-zajac/goprojectdemo/internal/app"
 	"github.com/m-zajac/goprojectdemo/internal/app/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestServiceMostActiveContributors(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name          string
-		setupMock     func(*mock.MockGithubClient)
-		language      string
-		projectsCount int
-		count         int
-		want          []app.ContributorStats
-		wantErr       bool
+		name           string
+		setupMock      func(*mock.MockForgeClient)
+		language       string
+		projectsCount  int
+		count          int
+		ranking        app.Ranking
+		window         app.TimeWindow
+		bestEffort     bool
+		timeout        time.Duration
+		degradedMargin time.Duration
+		loginFilter    *app.LoginFilter
+		identities     *app.IdentityResolver
+		exclude        []string
+		projectFilter  app.ProjectFilter
+		want           *app.ContributorsResult
+		wantErr        bool
 	}{
 		{
 			name: "invalid count",
-			setupMock: func(m *mock.MockGithubClient) {
+			setupMock: func(m *mock.MockForgeClient) {
 
 			},
 			language:      "go",
@@ -35,11 +45,25 @@ func TestServiceMostActiveContributors(t *testing.T) {
 			want:          nil,
 			wantErr:       true,
 		},
+		{
+			name: "invalid ranking mode",
+			setupMock: func(m *mock.MockForgeClient) {
+
+			},
+			language:      "go",
+			projectsCount: 1,
+			count:         1,
+			ranking: app.Ranking{
+				Mode: "stars",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "projects error from client",
-			setupMock: func(m *mock.MockGithubClient) {
+			setupMock: func(m *mock.MockForgeClient) {
 				m.EXPECT().
-					ProjectsByLanguage(gomock.Any(), "go", 3).
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 3).
 					Return(nil, errors.New("error"))
 			},
 			language:      "go",
@@ -50,9 +74,9 @@ func TestServiceMostActiveContributors(t *testing.T) {
 		},
 		{
 			name: "stats error from client",
-			setupMock: func(m *mock.MockGithubClient) {
+			setupMock: func(m *mock.MockForgeClient) {
 				m.EXPECT().
-					ProjectsByLanguage(gomock.Any(), "go", 2).
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
 					Return(
 						[]app.Project{
 							{
@@ -76,9 +100,9 @@ func TestServiceMostActiveContributors(t *testing.T) {
 		},
 		{
 			name: "client ok, return valid, sorted response",
-			setupMock: func(m *mock.MockGithubClient) {
+			setupMock: func(m *mock.MockForgeClient) {
 				m.EXPECT().
-					ProjectsByLanguage(gomock.Any(), "go", 1).
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 1).
 					Return(
 						[]app.Project{
 							{
@@ -122,44 +146,1582 @@ func TestServiceMostActiveContributors(t *testing.T) {
 			language:      "go",
 			projectsCount: 1,
 			count:         2,
-			want: []app.ContributorStats{
-				{
-					Commits: 5,
-					Contributor: app.Contributor{
-						ID:    2,
-						Login: "cont2",
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits: 5,
+						Contributor: app.Contributor{
+							ID:    2,
+							Login: "cont2",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project",
+									OwnerLogin: "owner",
+								},
+								Commits: 5,
+							},
+						},
+					},
+					{
+						Commits: 4,
+						Contributor: app.Contributor{
+							ID:    3,
+							Login: "cont3",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project",
+									OwnerLogin: "owner",
+								},
+								Commits: 4,
+							},
+						},
 					},
 				},
-				{
-					Commits: 4,
-					Contributor: app.Contributor{
-						ID:    3,
-						Login: "cont3",
+				Completeness: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "client ok, rank by net lines",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project1",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         2,
+								Name:       "project2",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits:   30,
+								Additions: 50,
+								Deletions: 40,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+							{
+								Commits:   2,
+								Additions: 100,
+								Deletions: 10,
+								Contributor: app.Contributor{
+									ID:    2,
+									Login: "cont2",
+								},
+							},
+						},
+						nil,
+					)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits:   1,
+								Additions: 5,
+								Deletions: 0,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+						},
+						nil,
+					)
+			},
+			language:      "go",
+			projectsCount: 2,
+			count:         2,
+			ranking: app.Ranking{
+				Mode: app.RankByNetLines,
+			},
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits:   2,
+						Additions: 100,
+						Deletions: 10,
+						Contributor: app.Contributor{
+							ID:    2,
+							Login: "cont2",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project1",
+									OwnerLogin: "owner",
+								},
+								Commits: 2,
+							},
+						},
+					},
+					{
+						Commits:   31,
+						Additions: 55,
+						Deletions: 40,
+						Contributor: app.Contributor{
+							ID:    1,
+							Login: "cont1",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project1",
+									OwnerLogin: "owner",
+								},
+								Commits: 30,
+							},
+							{
+								Project: app.Project{
+									ID:         2,
+									Name:       "project2",
+									OwnerLogin: "owner",
+								},
+								Commits: 1,
+							},
+						},
 					},
 				},
+				Completeness: 1,
 			},
 			wantErr: false,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
+		{
+			name: "client ok, count only contributions in time window",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 1).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
 
-			githubCli := mock.NewMockGithubClient(ctrl)
-			if tt.setupMock != nil {
-				tt.setupMock(githubCli)
-			}
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 10,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "old",
+								},
+								Weeks: []app.WeeklyStats{
+									{
+										Start:   time.Date(2015, 1, 4, 0, 0, 0, 0, time.UTC),
+										Commits: 10,
+									},
+								},
+							},
+							{
+								Commits: 3,
+								Contributor: app.Contributor{
+									ID:    2,
+									Login: "recent",
+								},
+								Weeks: []app.WeeklyStats{
+									{
+										Start:   time.Date(2015, 1, 4, 0, 0, 0, 0, time.UTC),
+										Commits: 1,
+									},
+									{
+										Start:   time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC),
+										Commits: 2,
+									},
+								},
+							},
+						},
+						nil,
+					)
+			},
+			language:      "go",
+			projectsCount: 1,
+			count:         2,
+			window: app.TimeWindow{
+				Since: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits: 2,
+						Contributor: app.Contributor{
+							ID:    2,
+							Login: "recent",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project",
+									OwnerLogin: "owner",
+								},
+								Commits: 2,
+							},
+						},
+					},
+				},
+				Completeness: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "client ok, bots and excluded logins filtered out",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 1).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
 
-			s := app.NewService(githubCli, time.Minute)
-			got, err := s.MostActiveContributors(
-				context.Background(),
-				tt.language,
-				tt.projectsCount,
-				tt.count,
-			)
-			assert.Equal(t, tt.wantErr, err != nil)
-			assert.Equal(t, tt.want, got)
-		})
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 30,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "dependabot[bot]",
+								},
+							},
+							{
+								Commits: 20,
+								Contributor: app.Contributor{
+									ID:    2,
+									Login: "build-ci",
+								},
+							},
+							{
+								Commits: 10,
+								Contributor: app.Contributor{
+									ID:    3,
+									Login: "alice",
+								},
+							},
+						},
+						nil,
+					)
+			},
+			language:      "go",
+			projectsCount: 1,
+			count:         3,
+			loginFilter:   mustNewLoginFilter(t, true),
+			exclude:       []string{"*-ci"},
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits: 10,
+						Contributor: app.Contributor{
+							ID:    3,
+							Login: "alice",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project",
+									OwnerLogin: "owner",
+								},
+								Commits: 10,
+							},
+						},
+					},
+				},
+				Filtered:     2,
+				Completeness: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid project filter",
+			setupMock: func(m *mock.MockForgeClient) {
+
+			},
+			language:      "go",
+			projectsCount: 1,
+			count:         1,
+			projectFilter: app.ProjectFilter{
+				MinStars: -1,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "project filter passed to client",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(
+						gomock.Any(),
+						app.ProjectsQuery{
+							Language: "go",
+							Filter: app.ProjectFilter{
+								MinStars:        10,
+								ExcludeArchived: true,
+							},
+						},
+						1,
+					).
+					Return(nil, errors.New("error"))
+			},
+			language:      "go",
+			projectsCount: 1,
+			count:         1,
+			projectFilter: app.ProjectFilter{
+				MinStars:        10,
+				ExcludeArchived: true,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid exclude pattern",
+			setupMock: func(m *mock.MockForgeClient) {
+
+			},
+			language:      "go",
+			projectsCount: 1,
+			count:         1,
+			exclude:       []string{"re:("},
+			want:          nil,
+			wantErr:       true,
+		},
+		{
+			name: "best effort, some projects failed",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 3).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project1",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         2,
+								Name:       "project2",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         3,
+								Name:       "project3",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 3,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+						},
+						nil,
+					)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					Return(nil, app.ScheduledForLaterError("scheduled"))
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project3", "owner").
+					Return(nil, errors.New("error"))
+			},
+			language:      "go",
+			projectsCount: 3,
+			count:         2,
+			bestEffort:    true,
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits: 3,
+						Contributor: app.Contributor{
+							ID:    1,
+							Login: "cont1",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project1",
+									OwnerLogin: "owner",
+								},
+								Commits: 3,
+							},
+						},
+					},
+				},
+				Skipped: []app.SkippedProject{
+					{
+						Project: app.Project{
+							ID:         2,
+							Name:       "project2",
+							OwnerLogin: "owner",
+						},
+						Reason: app.SkipReasonScheduled,
+					},
+					{
+						Project: app.Project{
+							ID:         3,
+							Name:       "project3",
+							OwnerLogin: "owner",
+						},
+						Reason: app.SkipReasonError,
+					},
+				},
+				Completeness: 1.0 / 3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "best effort, project timed out",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project1",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         2,
+								Name:       "project2",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 3,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+						},
+						nil,
+					)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					DoAndReturn(func(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+						<-ctx.Done()
+						return nil, ctx.Err()
+					})
+			},
+			language:      "go",
+			projectsCount: 2,
+			count:         2,
+			bestEffort:    true,
+			timeout:       50 * time.Millisecond,
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits: 3,
+						Contributor: app.Contributor{
+							ID:    1,
+							Login: "cont1",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project1",
+									OwnerLogin: "owner",
+								},
+								Commits: 3,
+							},
+						},
+					},
+				},
+				Skipped: []app.SkippedProject{
+					{
+						Project: app.Project{
+							ID:         2,
+							Name:       "project2",
+							OwnerLogin: "owner",
+						},
+						Reason: app.SkipReasonTimeout,
+					},
+				},
+				Completeness: 0.5,
+			},
+			wantErr: false,
+		},
+		{
+			name: "degraded mode, project timed out",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project1",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         2,
+								Name:       "project2",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 3,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+						},
+						nil,
+					)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					DoAndReturn(func(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+						<-ctx.Done()
+						return nil, ctx.Err()
+					})
+			},
+			language:       "go",
+			projectsCount:  2,
+			count:          2,
+			timeout:        time.Second,
+			degradedMargin: 950 * time.Millisecond,
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits: 3,
+						Contributor: app.Contributor{
+							ID:    1,
+							Login: "cont1",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project1",
+									OwnerLogin: "owner",
+								},
+								Commits: 3,
+							},
+						},
+					},
+				},
+				Skipped: []app.SkippedProject{
+					{
+						Project: app.Project{
+							ID:         2,
+							Name:       "project2",
+							OwnerLogin: "owner",
+						},
+						Reason: app.SkipReasonTimeout,
+					},
+				},
+				Completeness: 0.5,
+			},
+			wantErr: false,
+		},
+		{
+			name: "degraded mode, project failed",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project1",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         2,
+								Name:       "project2",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					Return(nil, nil).
+					MaxTimes(1)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					Return(nil, errors.New("error"))
+			},
+			language:       "go",
+			projectsCount:  2,
+			count:          2,
+			timeout:        time.Second,
+			degradedMargin: 950 * time.Millisecond,
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "removed project fails request",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project1",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         2,
+								Name:       "project2",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					AnyTimes().
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 3,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+						},
+						nil,
+					)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					Return(nil, app.NotFoundError("not found"))
+			},
+			language:      "go",
+			projectsCount: 2,
+			count:         2,
+			want:          nil,
+			wantErr:       true,
+		},
+		{
+			name: "removed project skipped in best-effort mode",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project1",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         2,
+								Name:       "project2",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 3,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+						},
+						nil,
+					)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					Return(nil, app.NotFoundError("not found"))
+			},
+			language:      "go",
+			projectsCount: 2,
+			count:         2,
+			bestEffort:    true,
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits: 3,
+						Contributor: app.Contributor{
+							ID:    1,
+							Login: "cont1",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project1",
+									OwnerLogin: "owner",
+								},
+								Commits: 3,
+							},
+						},
+					},
+				},
+				Skipped: []app.SkippedProject{
+					{
+						Project: app.Project{
+							ID:         2,
+							Name:       "project2",
+							OwnerLogin: "owner",
+						},
+						Reason: app.SkipReasonNotFound,
+					},
+				},
+				Completeness: 0.5,
+			},
+			wantErr: false,
+		},
+		{
+			name: "aliases merged into main identity",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project1",
+								OwnerLogin: "owner",
+							},
+							{
+								ID:         2,
+								Name:       "project2",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 3,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+							{
+								Commits: 4,
+								Contributor: app.Contributor{
+									ID:    2,
+									Login: "cont2",
+								},
+							},
+						},
+						nil,
+					)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 2,
+								Contributor: app.Contributor{
+									ID:    3,
+									Login: "Cont1-Work",
+								},
+							},
+						},
+						nil,
+					)
+			},
+			language:      "go",
+			projectsCount: 2,
+			count:         2,
+			identities:    mustNewIdentityResolver(t, map[string][]string{"cont1": {"cont1-work"}}),
+			want: &app.ContributorsResult{
+				Stats: []app.ContributorStats{
+					{
+						Commits: 5,
+						Contributor: app.Contributor{
+							ID:    1,
+							Login: "cont1",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project1",
+									OwnerLogin: "owner",
+								},
+								Commits: 3,
+							},
+							{
+								Project: app.Project{
+									ID:         2,
+									Name:       "project2",
+									OwnerLogin: "owner",
+								},
+								Commits: 2,
+							},
+						},
+						Aliases: []app.Contributor{
+							{
+								ID:    3,
+								Login: "Cont1-Work",
+							},
+						},
+					},
+					{
+						Commits: 4,
+						Contributor: app.Contributor{
+							ID:    2,
+							Login: "cont2",
+						},
+						Projects: []app.ProjectContribution{
+							{
+								Project: app.Project{
+									ID:         1,
+									Name:       "project1",
+									OwnerLogin: "owner",
+								},
+								Commits: 4,
+							},
+						},
+					},
+				},
+				Completeness: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "best effort, all projects failed",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 1).
+					Return(
+						[]app.Project{
+							{
+								ID:         1,
+								Name:       "project",
+								OwnerLogin: "owner",
+							},
+						},
+						nil,
+					)
+
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project", "owner").
+					Return(nil, app.ScheduledForLaterError("scheduled"))
+			},
+			language:      "go",
+			projectsCount: 1,
+			count:         1,
+			bestEffort:    true,
+			want:          nil,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			githubCli := mock.NewMockForgeClient(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(githubCli)
+			}
+
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = time.Minute
+			}
+			pool, err := app.NewWorkerPool(2, 0)
+			require.NoError(t, err)
+			s := app.NewService(app.Forges{app.DefaultForge: githubCli}, tt.loginFilter, tt.identities, pool, timeout, tt.degradedMargin)
+			got, err := s.MostActiveContributors(
+				context.Background(),
+				app.ContributorsQuery{
+					Language:      tt.language,
+					ProjectsCount: tt.projectsCount,
+					Count:         tt.count,
+					Ranking:       tt.ranking,
+					Window:        tt.window,
+					BestEffort:    tt.bestEffort,
+					Exclude:       tt.exclude,
+					ProjectFilter: tt.projectFilter,
+				},
+			)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if got != nil {
+				for i := range got.Skipped {
+					assert.Error(t, got.Skipped[i].Err)
+					got.Skipped[i].Err = nil
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServiceMostActiveContributorsCoalescing(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statsRequested := make(chan struct{})
+	release := make(chan struct{})
+	githubCli := mock.NewMockForgeClient(ctrl)
+	githubCli.EXPECT().
+		ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 1).
+		Return([]app.Project{{ID: 1, Name: "project", OwnerLogin: "owner"}}, nil).
+		Times(1)
+	githubCli.EXPECT().
+		StatsByProject(gomock.Any(), "project", "owner").
+		DoAndReturn(func(ctx context.Context, name string, owner string) ([]app.ContributorStats, error) {
+			close(statsRequested)
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return []app.ContributorStats{
+				{
+					Commits: 3,
+					Contributor: app.Contributor{
+						ID:    1,
+						Login: "cont1",
+					},
+				},
+				{
+					Commits: 2,
+					Contributor: app.Contributor{
+						ID:    2,
+						Login: "cont2",
+					},
+				},
+			}, nil
+		}).
+		Times(1)
+
+	s := app.NewService(app.Forges{app.DefaultForge: githubCli}, nil, nil, nil, time.Minute, 0)
+	q := app.ContributorsQuery{
+		Language:      "go",
+		ProjectsCount: 1,
+		Count:         1,
+	}
+
+	// First caller gives up waiting, computation continues for the others.
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	canceledResult := make(chan error)
+	go func() {
+		_, err := s.MostActiveContributors(canceledCtx, q)
+		canceledResult <- err
+	}()
+	<-statsRequested
+
+	type result struct {
+		res *app.ContributorsResult
+		err error
+	}
+	results := make(chan result)
+	for _, count := range []int{1, 2} {
+		q := q
+		q.Count = count
+		go func() {
+			res, err := s.MostActiveContributors(context.Background(), q)
+			results <- result{res, err}
+		}()
+	}
+
+	// Give other callers time to join the computation.
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-canceledResult)
+	close(release)
+
+	counts := make(map[int]bool)
+	for i := 0; i < 2; i++ {
+		r := <-results
+		require.NoError(t, r.err)
+		counts[len(r.res.Stats)] = true
+	}
+	assert.Equal(t, map[int]bool{1: true, 2: true}, counts)
+}
+
+func TestServiceContributorProfile(t *testing.T) {
+	t.Parallel()
+
+	projects := []app.Project{
+		{
+			ID:         1,
+			Name:       "project1",
+			OwnerLogin: "owner",
+		},
+		{
+			ID:         2,
+			Name:       "project2",
+			OwnerLogin: "owner",
+		},
+	}
+	setupValidMock := func(m *mock.MockForgeClient) {
+		m.EXPECT().
+			ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+			Return(projects, nil)
+		m.EXPECT().
+			StatsByProject(gomock.Any(), "project1", "owner").
+			Return(
+				[]app.ContributorStats{
+					{
+						Commits: 10,
+						Contributor: app.Contributor{
+							ID:    1,
+							Login: "cont1",
+						},
+					},
+					{
+						Commits: 4,
+						Contributor: app.Contributor{
+							ID:    2,
+							Login: "Cont2",
+						},
+					},
+				},
+				nil,
+			)
+		m.EXPECT().
+			StatsByProject(gomock.Any(), "project2", "owner").
+			Return(
+				[]app.ContributorStats{
+					{
+						Commits:   3,
+						Additions: 7,
+						Contributor: app.Contributor{
+							ID:    2,
+							Login: "Cont2",
+						},
+					},
+				},
+				nil,
+			)
+	}
+
+	tests := []struct {
+		name         string
+		setupMock    func(*mock.MockForgeClient)
+		login        string
+		want         *app.ContributorProfile
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{
+			name:    "empty login",
+			login:   "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "projects error from client",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(nil, errors.New("error"))
+			},
+			login:   "cont1",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:      "contributor found",
+			setupMock: setupValidMock,
+			login:     "cont2",
+			want: &app.ContributorProfile{
+				Contributor: app.Contributor{
+					ID:    2,
+					Login: "Cont2",
+				},
+				Language:          "go",
+				Rank:              2,
+				ContributorsCount: 2,
+				Commits:           7,
+				Additions:         7,
+				Projects: []app.ProjectContribution{
+					{
+						Project: projects[0],
+						Commits: 4,
+					},
+					{
+						Project: projects[1],
+						Commits: 3,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:         "contributor not found",
+			setupMock:    setupValidMock,
+			login:        "unknown",
+			want:         nil,
+			wantErr:      true,
+			wantNotFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			githubCli := mock.NewMockForgeClient(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(githubCli)
+			}
+
+			s := app.NewService(app.Forges{app.DefaultForge: githubCli}, nil, nil, nil, time.Minute, 0)
+			got, err := s.ContributorProfile(context.Background(), "", tt.login, "go", 2)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantNotFound, app.IsNotFoundError(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServiceTopProjects(t *testing.T) {
+	t.Parallel()
+
+	projects := []app.Project{
+		{
+			ID:         1,
+			Name:       "project1",
+			OwnerLogin: "owner",
+			Stars:      200,
+			URL:        "https://github.com/owner/project1",
+		},
+		{
+			ID:         2,
+			Name:       "project2",
+			OwnerLogin: "owner",
+			Stars:      100,
+			URL:        "https://github.com/owner/project2",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(*mock.MockForgeClient)
+		query     app.ProjectsQuery
+		want      []app.Project
+		wantErr   bool
+	}{
+		{
+			name: "invalid filter",
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter: app.ProjectFilter{
+					MinStars: -1,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error from client",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(nil, errors.New("error"))
+			},
+			query:   app.ProjectsQuery{Language: "go"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "valid response",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(
+						gomock.Any(),
+						app.ProjectsQuery{
+							Language: "go",
+							Filter: app.ProjectFilter{
+								ExcludeForks: true,
+							},
+						},
+						2,
+					).
+					Return(projects, nil)
+			},
+			query: app.ProjectsQuery{
+				Language: "go",
+				Filter: app.ProjectFilter{
+					ExcludeForks: true,
+				},
+			},
+			want:    projects,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			githubCli := mock.NewMockForgeClient(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(githubCli)
+			}
+
+			s := app.NewService(app.Forges{app.DefaultForge: githubCli}, nil, nil, nil, time.Minute, 0)
+			got, err := s.TopProjects(context.Background(), tt.query, 2)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServiceForges(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	project := app.Project{
+		ID:         1,
+		Name:       "project",
+		OwnerLogin: "group",
+	}
+	githubCli := mock.NewMockForgeClient(ctrl)
+	githubCli.EXPECT().
+		ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 1).
+		Return([]app.Project{project}, nil)
+	gitlabCli := mock.NewMockForgeClient(ctrl)
+	gitlabCli.EXPECT().
+		ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Forge: app.ForgeGitlab, Language: "go"}, 1).
+		Return([]app.Project{project}, nil)
+	gitlabCli.EXPECT().
+		StatsByProject(gomock.Any(), "project", "group").
+		Return([]app.ContributorStats{
+			{
+				Contributor: app.Contributor{ID: 1, Login: "Jane Doe"},
+				Commits:     10,
+			},
+		}, nil)
+
+	s := app.NewService(
+		app.Forges{
+			app.ForgeGithub: githubCli,
+			app.ForgeGitlab: gitlabCli,
+		},
+		nil,
+		nil,
+		nil,
+		time.Minute,
+		0,
+	)
+
+	projects, err := s.TopProjects(context.Background(), app.ProjectsQuery{Language: "go"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []app.Project{project}, projects)
+
+	result, err := s.MostActiveContributors(context.Background(), app.ContributorsQuery{
+		Forge:         app.ForgeGitlab,
+		Language:      "go",
+		ProjectsCount: 1,
+		Count:         1,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Stats, 1)
+	assert.Equal(t, "Jane Doe", result.Stats[0].Contributor.Login)
+	assert.Equal(t, 10, result.Stats[0].Commits)
+
+	// Gitlab stats have no weekly activity, so windowed queries are rejected instead of returning empty results.
+	_, err = s.MostActiveContributors(context.Background(), app.ContributorsQuery{
+		Forge:         app.ForgeGitlab,
+		Language:      "go",
+		ProjectsCount: 1,
+		Count:         1,
+		Window:        app.TimeWindow{Since: time.Now().Add(-4 * 7 * 24 * time.Hour)},
+	})
+	assert.True(t, app.IsInvalidRequestError(err))
+
+	_, err = s.TopProjects(context.Background(), app.ProjectsQuery{Forge: "bitbucket", Language: "go"}, 1)
+	assert.True(t, app.IsInvalidRequestError(err))
+}
+
+func TestServiceUnsupportedForgeStats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		forge string
+		query app.ContributorsQuery
+	}{
+		{
+			name:  "time window on gitlab",
+			forge: app.ForgeGitlab,
+			query: app.ContributorsQuery{
+				Window: app.TimeWindow{Since: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:  "additions on gitea",
+			forge: app.ForgeGitea,
+			query: app.ContributorsQuery{Ranking: app.Ranking{Mode: app.RankByAdditions}},
+		},
+		{
+			name:  "deletions on gitea",
+			forge: app.ForgeGitea,
+			query: app.ContributorsQuery{Ranking: app.Ranking{Mode: app.RankByDeletions}},
+		},
+		{
+			name:  "net lines on gitea",
+			forge: app.ForgeGitea,
+			query: app.ContributorsQuery{Ranking: app.Ranking{Mode: app.RankByNetLines}},
+		},
+		{
+			name:  "default score on gitea",
+			forge: app.ForgeGitea,
+			query: app.ContributorsQuery{Ranking: app.Ranking{Mode: app.RankByScore}},
+		},
+		{
+			name:  "score with line weights on gitea",
+			forge: app.ForgeGitea,
+			query: app.ContributorsQuery{
+				Ranking: app.Ranking{
+					Mode:    app.RankByScore,
+					Weights: app.ScoreWeights{Commits: 1, Deletions: 0.5},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := app.NewService(
+				app.Forges{tt.forge: mock.NewMockForgeClient(ctrl)},
+				nil,
+				nil,
+				nil,
+				time.Minute,
+				0,
+			)
+			q := tt.query
+			q.Forge = tt.forge
+			q.Language = "go"
+			q.ProjectsCount = 1
+			q.Count = 1
+
+			_, err := s.MostActiveContributors(context.Background(), q)
+			assert.True(t, app.IsInvalidRequestError(err))
+		})
+	}
+}
+
+func TestServiceHealthMetrics(t *testing.T) {
+	t.Parallel()
+
+	projects := []app.Project{
+		{
+			ID:         1,
+			Name:       "project1",
+			OwnerLogin: "owner",
+		},
+		{
+			ID:         2,
+			Name:       "project2",
+			OwnerLogin: "owner",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(*mock.MockForgeClient)
+		want      *app.HealthMetrics
+		wantErr   bool
+	}{
+		{
+			name: "projects error from client",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(nil, errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "valid response",
+			setupMock: func(m *mock.MockForgeClient) {
+				m.EXPECT().
+					ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 2).
+					Return(projects, nil)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project1", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 3,
+								Contributor: app.Contributor{
+									ID:    1,
+									Login: "cont1",
+								},
+							},
+							{
+								Commits: 1,
+								Contributor: app.Contributor{
+									ID:    2,
+									Login: "cont2",
+								},
+							},
+						},
+						nil,
+					)
+				m.EXPECT().
+					StatsByProject(gomock.Any(), "project2", "owner").
+					Return(
+						[]app.ContributorStats{
+							{
+								Commits: 4,
+								Contributor: app.Contributor{
+									ID:    2,
+									Login: "cont2",
+								},
+							},
+						},
+						nil,
+					)
+			},
+			want: &app.HealthMetrics{
+				Language: "go",
+				Overall: app.ConcentrationMetrics{
+					Contributors: 2,
+					Commits:      8,
+					BusFactor:    1,
+					Gini:         0.125,
+					Top1Share:    0.625,
+					Top5Share:    1,
+					Top10Share:   1,
+				},
+				Projects: []app.ProjectHealth{
+					{
+						Project: projects[0],
+						Metrics: app.ConcentrationMetrics{
+							Contributors: 2,
+							Commits:      4,
+							BusFactor:    1,
+							Gini:         0.25,
+							Top1Share:    0.75,
+							Top5Share:    1,
+							Top10Share:   1,
+						},
+					},
+					{
+						Project: projects[1],
+						Metrics: app.ConcentrationMetrics{
+							Contributors: 1,
+							Commits:      4,
+							BusFactor:    1,
+							Top1Share:    1,
+							Top5Share:    1,
+							Top10Share:   1,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			githubCli := mock.NewMockForgeClient(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(githubCli)
+			}
+
+			s := app.NewService(app.Forges{app.DefaultForge: githubCli}, nil, nil, nil, time.Minute, 0)
+			got, err := s.HealthMetrics(context.Background(), "", "go", 2)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func mustNewIdentityResolver(t *testing.T, aliases map[string][]string) *app.IdentityResolver {
+	r, err := app.NewIdentityResolver(aliases, nil)
+	if err != nil {
+		t.Fatalf("creating identity resolver: %v", err)
+	}
+	return r
+}
+
+func mustNewLoginFilter(t *testing.T, excludeBots bool) *app.LoginFilter {
+	f, err := app.NewLoginFilter(excludeBots, nil, nil)
+	if err != nil {
+		t.Fatalf("creating login filter: %v", err)
 	}
+	return f
 }
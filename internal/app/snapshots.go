@@ -0,0 +1,203 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SnapshotStore persists leaderboard snapshots.
+//go:generate mockgen -destination mock/snapshotstore.go -package mock github.com/m-zajac/goprojectdemo/internal/app SnapshotStore
+type SnapshotStore interface {
+	SaveSnapshot(s Snapshot) error
+	// SnapshotTimes returns times of snapshots stored for given language, in ascending order.
+	SnapshotTimes(language string) ([]time.Time, error)
+	// Snapshot returns snapshot taken at given time. Returns nil if there's no such snapshot.
+	Snapshot(language string, t time.Time) (*Snapshot, error)
+}
+
+// Snapshot holds leaderboard computed at given time.
+type Snapshot struct {
+	Language string
+	Time     time.Time
+	// Stats are ordered by rank.
+	Stats []ContributorStats
+}
+
+// RankChange describes contributor's position change between two snapshots.
+type RankChange struct {
+	Contributor Contributor
+	// PreviousRank is zero if contributor wasn't present in the older snapshot.
+	PreviousRank int
+	// Rank is zero if contributor isn't present in the newer snapshot.
+	Rank            int
+	PreviousCommits int
+	Commits         int
+}
+
+// Climbed returns number of positions contributor climbed. Negative value means contributor went down.
+// Returns zero for new entrants and drop-outs.
+func (c RankChange) Climbed() int {
+	if c.PreviousRank == 0 || c.Rank == 0 {
+		return 0
+	}
+	return c.PreviousRank - c.Rank
+}
+
+// SnapshotDiff holds differences between two leaderboard snapshots.
+type SnapshotDiff struct {
+	Language string
+	From     time.Time
+	To       time.Time
+	// Changes holds contributors present in both snapshots, ordered by current rank.
+	Changes []RankChange
+	// NewEntrants holds contributors present only in the newer snapshot, ordered by current rank.
+	NewEntrants []RankChange
+	// DropOuts holds contributors present only in the older snapshot, ordered by previous rank.
+	DropOuts []RankChange
+}
+
+// Snapshotter takes leaderboard snapshots and compares them.
+type Snapshotter struct {
+	service       *Service
+	store         SnapshotStore
+	projectsCount int
+	count         int
+}
+
+// NewSnapshotter creates new Snapshotter instance.
+// Snapshots contain top `count` contributors from top `projectsCount` projects.
+func NewSnapshotter(service *Service, store SnapshotStore, projectsCount int, count int) *Snapshotter {
+	return &Snapshotter{
+		service:       service,
+		store:         store,
+		projectsCount: projectsCount,
+		count:         count,
+	}
+}
+
+// TakeSnapshot computes most active contributors for given language and stores them as a new snapshot.
+func (s *Snapshotter) TakeSnapshot(ctx context.Context, language string) (*Snapshot, error) {
+	result, err := s.service.MostActiveContributors(ctx, ContributorsQuery{
+		Language:      language,
+		ProjectsCount: s.projectsCount,
+		Count:         s.count,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := Snapshot{
+		Language: language,
+		Time:     time.Now().UTC().Truncate(time.Second),
+		Stats:    result.Stats,
+	}
+	if err := s.store.SaveSnapshot(snapshot); err != nil {
+		return nil, fmt.Errorf("saving snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// SnapshotDiff compares snapshots taken at or before given times.
+// If `to` is zero, latest snapshot is used. If `from` is zero, snapshot preceding the `to` snapshot is used.
+func (s *Snapshotter) SnapshotDiff(ctx context.Context, language string, from, to time.Time) (*SnapshotDiff, error) {
+	if language == "" {
+		return nil, InvalidRequestError("language cannot be empty")
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, InvalidRequestError("from must be before to")
+	}
+
+	times, err := s.store.SnapshotTimes(language)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot times: %w", err)
+	}
+
+	toIdx := len(times) - 1
+	if !to.IsZero() {
+		toIdx = latestAtOrBefore(times, to)
+	}
+	fromIdx := toIdx - 1
+	if !from.IsZero() {
+		fromIdx = latestAtOrBefore(times, from)
+	}
+	if fromIdx < 0 || toIdx < 0 || fromIdx >= toIdx {
+		return nil, InvalidRequestError(fmt.Sprintf("not enough snapshots for language '%s' to compare", language))
+	}
+
+	fromSnapshot, err := s.snapshot(language, times[fromIdx])
+	if err != nil {
+		return nil, err
+	}
+	toSnapshot, err := s.snapshot(language, times[toIdx])
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffSnapshots(*fromSnapshot, *toSnapshot)
+	return &diff, nil
+}
+
+func (s *Snapshotter) snapshot(language string, t time.Time) (*Snapshot, error) {
+	snapshot, err := s.store.Snapshot(language, t)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot from %s: %w", t.Format(time.RFC3339), err)
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("snapshot from %s not found", t.Format(time.RFC3339))
+	}
+
+	return snapshot, nil
+}
+
+// latestAtOrBefore returns index of the latest of ascending times not after t, or -1 if there's none.
+func latestAtOrBefore(times []time.Time, t time.Time) int {
+	return sort.Search(len(times), func(i int) bool {
+		return times[i].After(t)
+	}) - 1
+}
+
+// diffSnapshots compares two snapshots. Contributors are matched by ID.
+func diffSnapshots(from, to Snapshot) SnapshotDiff {
+	diff := SnapshotDiff{
+		Language: to.Language,
+		From:     from.Time,
+		To:       to.Time,
+	}
+
+	previous := make(map[int]int, len(from.Stats))
+	for i, st := range from.Stats {
+		previous[st.Contributor.ID] = i
+	}
+	current := make(map[int]bool, len(to.Stats))
+	for i, st := range to.Stats {
+		current[st.Contributor.ID] = true
+		change := RankChange{
+			Contributor: st.Contributor,
+			Rank:        i + 1,
+			Commits:     st.Commits,
+		}
+		prevIdx, ok := previous[st.Contributor.ID]
+		if !ok {
+			diff.NewEntrants = append(diff.NewEntrants, change)
+			continue
+		}
+		change.PreviousRank = prevIdx + 1
+		change.PreviousCommits = from.Stats[prevIdx].Commits
+		diff.Changes = append(diff.Changes, change)
+	}
+	for i, st := range from.Stats {
+		if current[st.Contributor.ID] {
+			continue
+		}
+		diff.DropOuts = append(diff.DropOuts, RankChange{
+			Contributor:     st.Contributor,
+			PreviousRank:    i + 1,
+			PreviousCommits: st.Commits,
+		})
+	}
+
+	return diff
+}
@@ -0,0 +1,213 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/m-zajac/goprojectdemo/internal/app"
+	"github.com/m-zajac/goprojectdemo/internal/app/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotterTakeSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	githubCli := mock.NewMockForgeClient(ctrl)
+	githubCli.EXPECT().
+		ProjectsByLanguage(gomock.Any(), app.ProjectsQuery{Language: "go"}, 3).
+		Return([]app.Project{{ID: 1, Name: "project", OwnerLogin: "owner"}}, nil)
+	githubCli.EXPECT().
+		StatsByProject(gomock.Any(), "project", "owner").
+		Return(
+			[]app.ContributorStats{
+				{Contributor: app.Contributor{ID: 1, Login: "c1"}, Commits: 1},
+				{Contributor: app.Contributor{ID: 2, Login: "c2"}, Commits: 5},
+				{Contributor: app.Contributor{ID: 3, Login: "c3"}, Commits: 3},
+			},
+			nil,
+		)
+
+	var saved app.Snapshot
+	store := mock.NewMockSnapshotStore(ctrl)
+	store.EXPECT().
+		SaveSnapshot(gomock.Any()).
+		DoAndReturn(func(s app.Snapshot) error {
+			saved = s
+			return nil
+		})
+
+	before := time.Now().Add(-time.Second)
+	s := app.NewSnapshotter(app.NewService(app.Forges{app.DefaultForge: githubCli}, nil, nil, nil, time.Minute, 0), store, 3, 2)
+	got, err := s.TakeSnapshot(context.Background(), "go")
+	require.NoError(t, err)
+	assert.Equal(t, saved, *got)
+	assert.Equal(t, "go", got.Language)
+	assert.True(t, got.Time.After(before))
+	require.Len(t, got.Stats, 2)
+	assert.Equal(t, "c2", got.Stats[0].Contributor.Login)
+	assert.Equal(t, "c3", got.Stats[1].Contributor.Login)
+}
+
+func TestSnapshotterSnapshotDiff(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(7 * 24 * time.Hour)
+	t3 := t2.Add(7 * 24 * time.Hour)
+	contributor := func(id int) app.Contributor {
+		return app.Contributor{ID: id, Login: string(rune('a' + id))}
+	}
+	snapshots := map[time.Time]*app.Snapshot{
+		t1: {
+			Language: "go",
+			Time:     t1,
+			Stats: []app.ContributorStats{
+				{Contributor: contributor(1), Commits: 10},
+				{Contributor: contributor(2), Commits: 8},
+			},
+		},
+		t2: {
+			Language: "go",
+			Time:     t2,
+			Stats: []app.ContributorStats{
+				{Contributor: contributor(1), Commits: 12},
+				{Contributor: contributor(2), Commits: 11},
+				{Contributor: contributor(3), Commits: 5},
+			},
+		},
+		t3: {
+			Language: "go",
+			Time:     t3,
+			Stats: []app.ContributorStats{
+				{Contributor: contributor(3), Commits: 20},
+				{Contributor: contributor(1), Commits: 15},
+				{Contributor: contributor(4), Commits: 9},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		language    string
+		from        time.Time
+		to          time.Time
+		times       []time.Time
+		timesErr    error
+		want        *app.SnapshotDiff
+		wantErr     bool
+		wantInvalid bool
+	}{
+		{
+			name:        "empty language",
+			language:    "",
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "from after to",
+			language:    "go",
+			from:        t2,
+			to:          t1,
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:     "store error",
+			language: "go",
+			timesErr: errors.New("error"),
+			wantErr:  true,
+		},
+		{
+			name:        "single snapshot",
+			language:    "go",
+			times:       []time.Time{t1},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "to before first snapshot",
+			language:    "go",
+			to:          t1.Add(-time.Hour),
+			times:       []time.Time{t1, t2, t3},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:     "latest snapshots by default",
+			language: "go",
+			times:    []time.Time{t1, t2, t3},
+			want: &app.SnapshotDiff{
+				Language: "go",
+				From:     t2,
+				To:       t3,
+				Changes: []app.RankChange{
+					{Contributor: contributor(3), PreviousRank: 3, Rank: 1, PreviousCommits: 5, Commits: 20},
+					{Contributor: contributor(1), PreviousRank: 1, Rank: 2, PreviousCommits: 12, Commits: 15},
+				},
+				NewEntrants: []app.RankChange{
+					{Contributor: contributor(4), Rank: 3, Commits: 9},
+				},
+				DropOuts: []app.RankChange{
+					{Contributor: contributor(2), PreviousRank: 2, PreviousCommits: 11},
+				},
+			},
+		},
+		{
+			name:     "snapshots at or before given times",
+			language: "go",
+			from:     t1.Add(time.Hour),
+			to:       t2.Add(time.Hour),
+			times:    []time.Time{t1, t2, t3},
+			want: &app.SnapshotDiff{
+				Language: "go",
+				From:     t1,
+				To:       t2,
+				Changes: []app.RankChange{
+					{Contributor: contributor(1), PreviousRank: 1, Rank: 1, PreviousCommits: 10, Commits: 12},
+					{Contributor: contributor(2), PreviousRank: 2, Rank: 2, PreviousCommits: 8, Commits: 11},
+				},
+				NewEntrants: []app.RankChange{
+					{Contributor: contributor(3), Rank: 3, Commits: 5},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock.NewMockSnapshotStore(ctrl)
+			store.EXPECT().
+				SnapshotTimes(tt.language).
+				Return(tt.times, tt.timesErr).
+				MaxTimes(1)
+			store.EXPECT().
+				Snapshot(tt.language, gomock.Any()).
+				DoAndReturn(func(_ string, t time.Time) (*app.Snapshot, error) {
+					return snapshots[t], nil
+				}).
+				AnyTimes()
+
+			s := app.NewSnapshotter(nil, store, 3, 3)
+			got, err := s.SnapshotDiff(context.Background(), tt.language, tt.from, tt.to)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantInvalid, app.IsInvalidRequestError(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRankChangeClimbed(t *testing.T) {
+	assert.Equal(t, 2, app.RankChange{PreviousRank: 3, Rank: 1}.Climbed())
+	assert.Equal(t, -1, app.RankChange{PreviousRank: 1, Rank: 2}.Climbed())
+	assert.Equal(t, 0, app.RankChange{Rank: 2}.Climbed())
+	assert.Equal(t, 0, app.RankChange{PreviousRank: 2}.Climbed())
+}
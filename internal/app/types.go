@@ -1,10 +1,21 @@
 package app
 
+import "time"
+
 // Project entity.
 type Project struct {
-	ID         int
-	Name       string
-	OwnerLogin string
+	ID            int
+	Name          string
+	OwnerLogin    string
+	Stars         int
+	Forks         int
+	Description   string
+	URL           string
+	DefaultBranch string
+	// PushedAt is time of the last push to any of project's branches.
+	PushedAt time.Time
+	// License is SPDX identifier of project's license, empty if unknown.
+	License string
 }
 
 // Contributor entity.
@@ -17,4 +28,111 @@ type Contributor struct {
 type ContributorStats struct {
 	Contributor Contributor
 	Commits     int
+	Additions   int
+	Deletions   int
+	// Weeks holds weekly activity, if available.
+	Weeks []WeeklyStats
+	// Projects holds contributor's activity in each project making up the stats, if available.
+	Projects []ProjectContribution
+	// Aliases holds other accounts of the contributor, which stats were merged into these stats.
+	Aliases []Contributor
+}
+
+// ProjectContribution holds contributor's activity in a single project.
+type ProjectContribution struct {
+	Project Project
+	Commits int
+}
+
+// WeeklyStats holds contributor's activity in a single week.
+type WeeklyStats struct {
+	Start     time.Time
+	Commits   int
+	Additions int
+	Deletions int
+}
+
+// ContributorProfile describes contributor's activity in top projects of a language.
+type ContributorProfile struct {
+	Contributor Contributor
+	Language    string
+	// Rank is contributor's position in commits ranking, starting from 1.
+	Rank int
+	// ContributorsCount is the number of all ranked contributors.
+	ContributorsCount int
+	Commits           int
+	Additions         int
+	Deletions         int
+	Projects          []ProjectContribution
+	// Aliases holds other accounts of the contributor, which stats were merged into the profile.
+	Aliases []Contributor
+}
+
+// ContributorsQuery describes which contributors should be returned by Service.MostActiveContributors.
+type ContributorsQuery struct {
+	// Forge hosting the projects. Empty means DefaultForge.
+	Forge string
+	// Language of the projects taken into account.
+	Language string
+	// ProjectsCount is the number of top projects (by stars) taken into account.
+	ProjectsCount int
+	// ProjectFilter narrows down projects taken into account.
+	ProjectFilter ProjectFilter
+	// Count is the maximum number of returned contributors.
+	Count int
+	// Ranking tells how contributors are ordered. Zero value ranks by commits.
+	Ranking Ranking
+	// Window limits contributions to given period of time. Zero value means all-time stats.
+	Window TimeWindow
+	// BestEffort enables partial results. Projects which stats couldn't be retrieved are skipped
+	// instead of failing the whole query.
+	BestEffort bool
+	// Exclude holds additional login patterns of contributors excluded from results, even if they are allowed
+	// by service's LoginFilter. See LoginFilter for patterns syntax.
+	Exclude []string
+}
+
+// ContributorsResult is the result of Service.MostActiveContributors.
+type ContributorsResult struct {
+	Stats []ContributorStats
+	// Skipped lists projects not taken into account. Can be non-empty only in best-effort mode,
+	// or in degraded mode if projects' stats weren't retrieved before deadline.
+	Skipped []SkippedProject
+	// Filtered is the number of contributors excluded from results by login filters.
+	Filtered int
+	// Completeness is the share of projects which stats are taken into account, in range <0..1>.
+	Completeness float64
+}
+
+// Incomplete tells if some projects were skipped.
+func (r ContributorsResult) Incomplete() bool {
+	return len(r.Skipped) > 0
+}
+
+// Partial tells if some projects were skipped, because their stats weren't retrieved in time.
+func (r ContributorsResult) Partial() bool {
+	for _, s := range r.Skipped {
+		if s.Reason == SkipReasonTimeout {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipReason tells why project was skipped.
+type SkipReason string
+
+// Available skip reasons.
+const (
+	SkipReasonError     SkipReason = "error"
+	SkipReasonScheduled SkipReason = "scheduled"
+	SkipReasonTimeout   SkipReason = "timeout"
+	SkipReasonNotFound  SkipReason = "not_found"
+)
+
+// SkippedProject describes project which stats weren't taken into account.
+type SkippedProject struct {
+	Project Project
+	Reason  SkipReason
+	Err     error
 }
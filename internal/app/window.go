@@ -0,0 +1,77 @@
+package app
+
+import "time"
+
+const week = 7 * 24 * time.Hour
+
+// TimeWindow limits contributions to given period of time. Zero value means all-time.
+//
+// Contributions are counted with weekly granularity: every week overlapping the window is taken into account.
+type TimeWindow struct {
+	// Since is the beginning of the window. Zero value means no lower bound.
+	Since time.Time
+	// Until is the end of the window. Zero value means no upper bound.
+	Until time.Time
+}
+
+// NewTimeWindow creates time window from api params.
+// If weeks is greater than zero, window covers last `weeks` weeks before now; since must be empty in this case.
+func NewTimeWindow(since time.Time, until time.Time, weeks int, now time.Time) (TimeWindow, error) {
+	if weeks < 0 {
+		return TimeWindow{}, InvalidRequestError("weeks cannot be negative")
+	}
+	if weeks > 0 {
+		if !since.IsZero() {
+			return TimeWindow{}, InvalidRequestError("weeks and since cannot be used together")
+		}
+		since = now.Add(-time.Duration(weeks) * week)
+	}
+
+	w := TimeWindow{
+		Since: since,
+		Until: until,
+	}
+	if err := w.validate(); err != nil {
+		return TimeWindow{}, err
+	}
+
+	return w, nil
+}
+
+// IsZero tells if window is not limited.
+func (w TimeWindow) IsZero() bool {
+	return w.Since.IsZero() && w.Until.IsZero()
+}
+
+// Apply returns stats counting only weeks overlapping the window.
+// If window is zero, stats are returned unchanged.
+func (w TimeWindow) Apply(s ContributorStats) ContributorStats {
+	if w.IsZero() {
+		return s
+	}
+
+	result := ContributorStats{
+		Contributor: s.Contributor,
+	}
+	for _, ws := range s.Weeks {
+		if !w.Since.IsZero() && !ws.Start.Add(week).After(w.Since) {
+			continue
+		}
+		if !w.Until.IsZero() && !ws.Start.Before(w.Until) {
+			continue
+		}
+		result.Commits += ws.Commits
+		result.Additions += ws.Additions
+		result.Deletions += ws.Deletions
+		result.Weeks = append(result.Weeks, ws)
+	}
+
+	return result
+}
+
+func (w TimeWindow) validate() error {
+	if !w.Since.IsZero() && !w.Until.IsZero() && !w.Until.After(w.Since) {
+		return InvalidRequestError("time window end must be after its beginning")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTimeWindow(t *testing.T) {
+	now := time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	w, err := NewTimeWindow(time.Time{}, time.Time{}, 0, now)
+	require.NoError(t, err)
+	assert.True(t, w.IsZero())
+
+	w, err = NewTimeWindow(time.Time{}, time.Time{}, 2, now)
+	require.NoError(t, err)
+	assert.Equal(t, TimeWindow{Since: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)}, w)
+
+	_, err = NewTimeWindow(since, time.Time{}, 2, now)
+	assert.True(t, IsInvalidRequestError(err))
+
+	_, err = NewTimeWindow(time.Time{}, time.Time{}, -1, now)
+	assert.True(t, IsInvalidRequestError(err))
+
+	_, err = NewTimeWindow(since, since.Add(-time.Hour), 0, now)
+	assert.True(t, IsInvalidRequestError(err))
+}
+
+func TestTimeWindowApply(t *testing.T) {
+	stats := ContributorStats{
+		Contributor: Contributor{
+			ID:    1,
+			Login: "x",
+		},
+		Commits:   6,
+		Additions: 60,
+		Deletions: 6,
+		Weeks: []WeeklyStats{
+			{
+				Start:     time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC),
+				Commits:   1,
+				Additions: 10,
+				Deletions: 1,
+			},
+			{
+				Start:     time.Date(2020, 1, 12, 0, 0, 0, 0, time.UTC),
+				Commits:   2,
+				Additions: 20,
+				Deletions: 2,
+			},
+			{
+				Start:     time.Date(2020, 1, 19, 0, 0, 0, 0, time.UTC),
+				Commits:   3,
+				Additions: 30,
+				Deletions: 3,
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		window TimeWindow
+		want   ContributorStats
+	}{
+		{
+			name:   "zero window",
+			window: TimeWindow{},
+			want:   stats,
+		},
+		{
+			name: "since in the middle of a week",
+			window: TimeWindow{
+				Since: time.Date(2020, 1, 14, 0, 0, 0, 0, time.UTC),
+			},
+			want: ContributorStats{
+				Contributor: stats.Contributor,
+				Commits:     5,
+				Additions:   50,
+				Deletions:   5,
+				Weeks:       stats.Weeks[1:],
+			},
+		},
+		{
+			name: "since and until",
+			window: TimeWindow{
+				Since: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2020, 1, 12, 0, 0, 0, 0, time.UTC),
+			},
+			want: ContributorStats{
+				Contributor: stats.Contributor,
+				Commits:     1,
+				Additions:   10,
+				Deletions:   1,
+				Weeks:       stats.Weeks[:1],
+			},
+		},
+		{
+			name: "no weeks in window",
+			window: TimeWindow{
+				Since: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: ContributorStats{
+				Contributor: stats.Contributor,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.window.Apply(stats))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package database
+
+// KVStore provides simple kv data storage.
+type KVStore interface {
+	ReadKey(key []byte) ([]byte, error)
+	UpdateKey(key []byte, data []byte) error
+}
+
+// PrefixedKVStore wraps kv store and prefixes all keys with given prefix.
+// Allows sharing single store by many clients without key collisions.
+type PrefixedKVStore struct {
+	store  KVStore
+	prefix []byte
+}
+
+// NewPrefixedKVStore creates new PrefixedKVStore instance.
+func NewPrefixedKVStore(store KVStore, prefix string) *PrefixedKVStore {
+	return &PrefixedKVStore{
+		store:  store,
+		prefix: []byte(prefix),
+	}
+}
+
+// ReadKey returns data saved for given key. Returns null if there's no data stored.
+func (s *PrefixedKVStore) ReadKey(key []byte) ([]byte, error) {
+	return s.store.ReadKey(s.key(key))
+}
+
+// UpdateKey stores given data under given key.
+func (s *PrefixedKVStore) UpdateKey(key []byte, data []byte) error {
+	return s.store.UpdateKey(s.key(key), data)
+}
+
+func (s *PrefixedKVStore) key(key []byte) []byte {
+	k := make([]byte, 0, len(s.prefix)+len(key))
+	k = append(k, s.prefix...)
+	return append(k, key...)
+}
@@ -0,0 +1,83 @@
+// Package singleflight provides duplicate call suppression.
+package singleflight
+
+import (
+	"context"
+	"sync"
+)
+
+// Group coalesces concurrent calls with the same key into a single execution.
+//
+// Unlike golang.org/x/sync/singleflight, each caller can stop waiting when its context is done.
+// Execution runs with its own context, canceled only when all waiting callers are gone.
+type Group struct {
+	m     sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	val interface{}
+	err error
+}
+
+// Do executes fn, unless execution for given key is already in flight. In that case it waits for its result.
+// Returned shared flag tells if the result was shared with other callers.
+// Returned value may be shared, so callers must not modify it.
+func (g *Group) Do(
+	ctx context.Context,
+	key string,
+	fn func(ctx context.Context) (interface{}, error),
+) (v interface{}, shared bool, err error) {
+	g.m.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	c, shared := g.calls[key]
+	if !shared {
+		fnCtx, cancel := context.WithCancel(context.Background())
+		c = &call{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		g.calls[key] = c
+		go g.execute(fnCtx, key, c, fn)
+	}
+	c.waiters++
+	g.m.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, shared, c.err
+	case <-ctx.Done():
+		g.m.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			g.forget(key, c)
+		}
+		g.m.Unlock()
+		return nil, shared, ctx.Err()
+	}
+}
+
+func (g *Group) execute(ctx context.Context, key string, c *call, fn func(ctx context.Context) (interface{}, error)) {
+	c.val, c.err = fn(ctx)
+	c.cancel()
+
+	g.m.Lock()
+	g.forget(key, c)
+	g.m.Unlock()
+
+	close(c.done)
+}
+
+// forget removes call from in-flight calls, unless it was already replaced. Must be called with lock held.
+func (g *Group) forget(key string, c *call) {
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
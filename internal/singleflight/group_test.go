@@ -0,0 +1,155 @@
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupDo(t *testing.T) {
+	t.Parallel()
+
+	var g Group
+	var executions int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&executions, 1)
+		<-release
+		return "value", nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	var sharedCount int32
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, shared, err := g.Do(context.Background(), "key", fn)
+			assert.NoError(t, err)
+			assert.Equal(t, "value", v)
+			if shared {
+				atomic.AddInt32(&sharedCount, 1)
+			}
+		}()
+	}
+
+	// Wait until all callers are waiting for the execution.
+	waitForWaiters(t, &g, "key", callers)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&executions))
+	assert.Equal(t, int32(callers-1), atomic.LoadInt32(&sharedCount))
+
+	// Finished call is forgotten.
+	_, shared, err := g.Do(context.Background(), "key", fn)
+	assert.NoError(t, err)
+	assert.False(t, shared)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&executions))
+}
+
+func TestGroupDoError(t *testing.T) {
+	t.Parallel()
+
+	var g Group
+	wantErr := errors.New("error")
+	_, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		return nil, wantErr
+	})
+	assert.Equal(t, wantErr, err)
+}
+
+func TestGroupDoCallerCanceled(t *testing.T) {
+	t.Parallel()
+
+	var g Group
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			close(canceled)
+			return nil, ctx.Err()
+		case <-release:
+			return "value", nil
+		}
+	}
+
+	// First caller gives up, but execution continues for the second one.
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	res1 := make(chan error)
+	go func() {
+		_, _, err := g.Do(ctx1, "key", fn)
+		res1 <- err
+	}()
+	<-started
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	res2 := make(chan interface{})
+	go func() {
+		v, shared, err := g.Do(ctx2, "key", fn)
+		assert.NoError(t, err)
+		assert.True(t, shared)
+		res2 <- v
+	}()
+	waitForWaiters(t, &g, "key", 2)
+
+	cancel1()
+	assert.Equal(t, context.Canceled, <-res1)
+	select {
+	case <-canceled:
+		t.Fatal("execution canceled while caller is still waiting")
+	default:
+	}
+
+	close(release)
+	assert.Equal(t, "value", <-res2)
+}
+
+func TestGroupDoAllCallersCanceled(t *testing.T) {
+	t.Parallel()
+
+	var g Group
+	canceled := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err := g.Do(ctx, "key", fn)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("execution not canceled")
+	}
+}
+
+// waitForWaiters waits until call for given key has given number of waiting callers.
+func waitForWaiters(t *testing.T, g *Group, key string, waiters int) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.m.Lock()
+		c, ok := g.calls[key]
+		done := ok && c.waiters == waiters
+		g.m.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timeout waiting for %d callers", waiters)
+}